@@ -0,0 +1,91 @@
+// MIT License
+//
+// Copyright (c) 2021 Iván Szkiba
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Package canon contains canonicalizers for common kinds of names, so that
+// trivial variations of the same entity always map to the same NBID.
+//
+// Each canonicalizer (URL, Email, Host, Phone) is paired with a constructor
+// (NewURL, NewEmail, NewHost, NewPhone) deriving the NBID of the canonical form
+// in a well-known namespace of the kind, using nbid.NewNamespaced.
+// The same string therefore yields different NBIDs as URL and as email address.
+//
+// Canonical forms are part of the stability guarantee of the package:
+// the canonical form (and the NBID) of a valid input will not change in
+// future versions.
+package canon
+
+import (
+	"errors"
+
+	"github.com/szkiba/nbid"
+)
+
+var (
+	// ErrInvalidURL is returned when a URL cannot be canonicalized.
+	ErrInvalidURL = errors.New("canon: invalid URL")
+
+	// ErrInvalidEmail is returned when an email address cannot be canonicalized.
+	ErrInvalidEmail = errors.New("canon: invalid email address")
+
+	// ErrInvalidHost is returned when a host name cannot be canonicalized.
+	ErrInvalidHost = errors.New("canon: invalid host name")
+
+	// ErrInvalidPhone is returned when a phone number cannot be canonicalized.
+	ErrInvalidPhone = errors.New("canon: invalid phone number")
+)
+
+// Well-known namespaces of the canonical kinds.
+var (
+	NamespaceURL   = nbid.New([]byte("github.com/szkiba/nbid/canon#url"))
+	NamespaceEmail = nbid.New([]byte("github.com/szkiba/nbid/canon#email"))
+	NamespaceHost  = nbid.New([]byte("github.com/szkiba/nbid/canon#host"))
+	NamespacePhone = nbid.New([]byte("github.com/szkiba/nbid/canon#phone"))
+)
+
+func newID(ns nbid.NBID, canonical func(string) (string, error), s string) (nbid.NBID, error) {
+	c, err := canonical(s)
+	if err != nil {
+		return nbid.Nil, err
+	}
+
+	return nbid.NewNamespaced(ns, []byte(c)), nil
+}
+
+// NewURL returns the NBID of the canonical form of the URL s in NamespaceURL.
+func NewURL(s string) (nbid.NBID, error) {
+	return newID(NamespaceURL, URL, s)
+}
+
+// NewEmail returns the NBID of the canonical form of the email address s in NamespaceEmail.
+func NewEmail(s string) (nbid.NBID, error) {
+	return newID(NamespaceEmail, Email, s)
+}
+
+// NewHost returns the NBID of the canonical form of the host name s in NamespaceHost.
+func NewHost(s string) (nbid.NBID, error) {
+	return newID(NamespaceHost, Host, s)
+}
+
+// NewPhone returns the NBID of the canonical form of the phone number s in NamespacePhone.
+func NewPhone(s string) (nbid.NBID, error) {
+	return newID(NamespacePhone, Phone, s)
+}
@@ -0,0 +1,74 @@
+// MIT License
+//
+// Copyright (c) 2021 Iván Szkiba
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package canon_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/szkiba/nbid"
+	"github.com/szkiba/nbid/canon"
+)
+
+func TestConstructors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		fn   func(string) (nbid.NBID, error)
+		ns   nbid.NBID
+		a    string
+		b    string
+	}{
+		{name: "url", fn: canon.NewURL, ns: canon.NamespaceURL, a: "HTTPS://Example.com:443/a/?y=1&x=2", b: "https://example.com/a?x=2&y=1"},
+		{name: "email", fn: canon.NewEmail, ns: canon.NamespaceEmail, a: "Alice+x@Example.com", b: "alice@example.com"},
+		{name: "host", fn: canon.NewHost, ns: canon.NamespaceHost, a: "Bücher.Example.", b: "xn--bcher-kva.example"},
+		{name: "phone", fn: canon.NewPhone, ns: canon.NamespacePhone, a: "0036 1 234 5678", b: "+3612345678"},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			a, err := tt.fn(tt.a)
+			assert.Nil(t, err)
+
+			b, err := tt.fn(tt.b)
+			assert.Nil(t, err)
+
+			assert.Equal(t, a, b)
+			assert.Equal(t, nbid.NewNamespaced(tt.ns, []byte(tt.b)), a)
+
+			_, err = tt.fn("")
+			assert.Error(t, err)
+		})
+	}
+
+	// namespaces are stable
+	assert.Equal(t, nbid.New([]byte("github.com/szkiba/nbid/canon#url")), canon.NamespaceURL)
+
+	// same string in different namespaces
+	h, _ := canon.NewHost("example.com")
+	u, _ := canon.NewURL("http://example.com")
+	assert.NotEqual(t, h, u)
+}
@@ -0,0 +1,66 @@
+// MIT License
+//
+// Copyright (c) 2021 Iván Szkiba
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package canon
+
+import (
+	"fmt"
+	"net/mail"
+	"strings"
+)
+
+// Email returns the canonical form of an email address.
+//
+// A display name is accepted and dropped ("Alice <alice@example.com>").
+// The domain is canonicalized by Host, the local part is normalized
+// (NFKC and case folding) and plus-addressing tags are removed, so
+// "Alice+news@Example.COM" and "alice@example.com" are the same.
+func Email(s string) (string, error) {
+	addr, err := mail.ParseAddress(strings.TrimSpace(s))
+	if err != nil {
+		return "", fmt.Errorf("%w: %q: %v", ErrInvalidEmail, s, err)
+	}
+
+	at := strings.LastIndexByte(addr.Address, '@')
+	if at <= 0 {
+		return "", fmt.Errorf("%w: %q", ErrInvalidEmail, s)
+	}
+
+	local, domain := addr.Address[:at], addr.Address[at+1:]
+
+	domain, err = Host(domain)
+	if err != nil {
+		return "", fmt.Errorf("%w: %q: %v", ErrInvalidEmail, s, err)
+	}
+
+	local = hostNormalizer.Normalize(local)
+
+	if plus := strings.IndexByte(local, '+'); plus >= 0 {
+		local = local[:plus]
+	}
+
+	if local == "" {
+		return "", fmt.Errorf("%w: %q: empty local part", ErrInvalidEmail, s)
+	}
+
+	return local + "@" + domain, nil
+}
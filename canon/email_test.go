@@ -0,0 +1,66 @@
+// MIT License
+//
+// Copyright (c) 2021 Iván Szkiba
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package canon_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/szkiba/nbid/canon"
+)
+
+func TestEmail(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		in      string
+		out     string
+		wantErr bool
+	}{
+		{in: "alice@example.com", out: "alice@example.com"},
+		{in: "Alice@Example.COM", out: "alice@example.com"},
+		{in: " Alice+news@Example.COM ", out: "alice@example.com"},
+		{in: "Alice <alice+x@example.com>", out: "alice@example.com"},
+		{in: "jose@bücher.example", out: "jose@xn--bcher-kva.example"},
+		{in: "alice", wantErr: true},
+		{in: "+news@example.com", wantErr: true},
+		{in: "alice@-example.com", wantErr: true},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.in, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := canon.Email(tt.in)
+			if tt.wantErr {
+				assert.True(t, errors.Is(err, canon.ErrInvalidEmail), err)
+
+				return
+			}
+
+			assert.Nil(t, err)
+			assert.Equal(t, tt.out, got)
+		})
+	}
+}
@@ -0,0 +1,225 @@
+// MIT License
+//
+// Copyright (c) 2021 Iván Szkiba
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package canon
+
+import (
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/szkiba/nbid"
+)
+
+const (
+	maxHostLen  = 253
+	maxLabelLen = 63
+	acePrefix   = "xn--"
+)
+
+var hostNormalizer = nbid.Normalizer{Form: nbid.NFKC, FoldCase: true}
+
+// Host returns the canonical form of a DNS host name.
+//
+// The canonical form is lower case ASCII without trailing dot. Internationalized
+// labels are normalized (NFKC and case folding) and converted to their punycode
+// A-label form, so "Bücher.Example." and "xn--bcher-kva.example" are the same.
+// IP addresses are returned in their canonical textual form (IPv6 without brackets).
+func Host(s string) (string, error) {
+	host := strings.TrimSuffix(s, ".")
+
+	if ip := net.ParseIP(strings.TrimSuffix(strings.TrimPrefix(host, "["), "]")); ip != nil {
+		return ip.String(), nil
+	}
+
+	if host == "" {
+		return "", fmt.Errorf("%w: %q", ErrInvalidHost, s)
+	}
+
+	labels := strings.Split(host, ".")
+
+	for i, label := range labels {
+		ace, err := toASCII(hostNormalizer.Normalize(label))
+		if err != nil || !validLabel(ace) {
+			return "", fmt.Errorf("%w: %q: invalid label %q", ErrInvalidHost, s, label)
+		}
+
+		labels[i] = ace
+	}
+
+	host = strings.Join(labels, ".")
+	if len(host) > maxHostLen {
+		return "", fmt.Errorf("%w: %q: too long", ErrInvalidHost, s)
+	}
+
+	return host, nil
+}
+
+// validLabel reports whether label is a valid LDH (letters, digits, hyphen) label.
+// Underscores are accepted, as they are common in service names.
+func validLabel(label string) bool {
+	if label == "" || len(label) > maxLabelLen || label[0] == '-' || label[len(label)-1] == '-' {
+		return false
+	}
+
+	for i := 0; i < len(label); i++ {
+		c := label[i]
+		if (c < 'a' || c > 'z') && (c < '0' || c > '9') && c != '-' && c != '_' {
+			return false
+		}
+	}
+
+	return true
+}
+
+// toASCII converts a label to its A-label form (RFC 3492 punycode with "xn--" prefix).
+// ASCII labels are returned as is.
+func toASCII(label string) (string, error) {
+	for i := 0; i < len(label); i++ {
+		if label[i] >= 0x80 {
+			enc, err := punycode(label)
+			if err != nil {
+				return "", err
+			}
+
+			return acePrefix + enc, nil
+		}
+	}
+
+	return label, nil
+}
+
+// Punycode parameters (RFC 3492 section 5).
+const (
+	pcBase        = 36
+	pcTMin        = 1
+	pcTMax        = 26
+	pcSkew        = 38
+	pcDamp        = 700
+	pcInitialBias = 72
+	pcInitialN    = 128
+)
+
+// punycode encodes s using the RFC 3492 Bootstring algorithm.
+func punycode(s string) (string, error) {
+	runes := []rune(s)
+	out := make([]byte, 0, len(s)+1)
+
+	for _, r := range runes {
+		if r < pcInitialN {
+			out = append(out, byte(r))
+		}
+	}
+
+	basic := len(out)
+	handled := basic
+
+	if basic > 0 {
+		out = append(out, '-')
+	}
+
+	n, delta, bias := rune(pcInitialN), 0, pcInitialBias
+
+	for handled < len(runes) {
+		m := rune(0x7fffffff)
+
+		for _, r := range runes {
+			if r >= n && r < m {
+				m = r
+			}
+		}
+
+		if int(m-n) > (0x7fffffff-delta)/(handled+1) {
+			return "", fmt.Errorf("%w: punycode overflow", ErrInvalidHost)
+		}
+
+		delta += int(m-n) * (handled + 1)
+		n = m
+
+		for _, r := range runes {
+			if r < n {
+				delta++
+			}
+
+			if r != n {
+				continue
+			}
+
+			q := delta
+
+			for k := pcBase; ; k += pcBase {
+				t := k - bias
+
+				switch {
+				case t < pcTMin:
+					t = pcTMin
+				case t > pcTMax:
+					t = pcTMax
+				}
+
+				if q < t {
+					break
+				}
+
+				out = append(out, pcDigit(t+(q-t)%(pcBase-t)))
+				q = (q - t) / (pcBase - t)
+			}
+
+			out = append(out, pcDigit(q))
+			bias = pcAdapt(delta, handled+1, handled == basic)
+			delta = 0
+			handled++
+		}
+
+		delta++
+		n++
+	}
+
+	return string(out), nil
+}
+
+func pcDigit(d int) byte {
+	if d < 26 { //nolint:gomnd
+		return byte('a' + d)
+	}
+
+	return byte('0' + d - 26) //nolint:gomnd
+}
+
+func pcAdapt(delta, numPoints int, first bool) int {
+	if first {
+		delta /= pcDamp
+	} else {
+		delta /= 2
+	}
+
+	delta += delta / numPoints
+
+	k := 0
+
+	for delta > ((pcBase-pcTMin)*pcTMax)/2 {
+		delta /= pcBase - pcTMin
+		k += pcBase
+	}
+
+	return k + (pcBase-pcTMin+1)*delta/(delta+pcSkew)
+}
@@ -0,0 +1,74 @@
+// MIT License
+//
+// Copyright (c) 2021 Iván Szkiba
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package canon_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/szkiba/nbid/canon"
+)
+
+func TestHost(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		in      string
+		out     string
+		wantErr bool
+	}{
+		{in: "Example.COM.", out: "example.com"},
+		{in: "_sip._tcp.example.com", out: "_sip._tcp.example.com"},
+		{in: "Bücher.Example", out: "xn--bcher-kva.example"},
+		{in: "xn--bcher-kva.example", out: "xn--bcher-kva.example"},
+		{in: "München.de", out: "xn--mnchen-3ya.de"},
+		// RFC 3492 7.1 sample (B) Chinese (simplified)
+		{in: "他们为什么不说中文", out: "xn--ihqwcrb4cv8a8dqg056pqjye"},
+		// RFC 3492 7.1 sample (L) 3<nen>B<gumi><kinpachi><sensei>
+		{in: "3年B組金八先生", out: "xn--3b-ww4c5e180e575a65lsy2b"},
+		{in: "192.168.0.1", out: "192.168.0.1"},
+		{in: "[2001:DB8::0:1]", out: "2001:db8::1"},
+		{in: "", wantErr: true},
+		{in: "a..b", wantErr: true},
+		{in: "-a.b", wantErr: true},
+		{in: "a b.c", wantErr: true},
+		{in: "aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa.com", wantErr: true},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.in, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := canon.Host(tt.in)
+			if tt.wantErr {
+				assert.True(t, errors.Is(err, canon.ErrInvalidHost), err)
+
+				return
+			}
+
+			assert.Nil(t, err)
+			assert.Equal(t, tt.out, got)
+		})
+	}
+}
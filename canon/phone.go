@@ -0,0 +1,93 @@
+// MIT License
+//
+// Copyright (c) 2021 Iván Szkiba
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package canon
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	minPhoneDigits       = 7
+	maxPhoneDigits       = 15 // E.164 limit including country code
+	maxCountryCodeDigits = 3
+
+	trunkPrefix = "(0)"
+)
+
+// Phone returns the canonical E.164 form of an international phone number, e.g. "+3612345678".
+//
+// The number must start with "+" or the "00" international call prefix followed by
+// the country code. Spaces, hyphens, dots, slashes and parentheses are removed.
+// A "(0)" trunk prefix directly after the country code is dropped, so
+// "+44 (0)20 7946 0958" is the same as "+44 20 7946 0958".
+// National numbers without country code are rejected, as they cannot be
+// canonicalized without knowing the country.
+func Phone(s string) (string, error) {
+	in := s
+	s = strings.TrimSpace(s)
+
+	switch {
+	case strings.HasPrefix(s, "+"):
+		s = s[1:]
+	case strings.HasPrefix(s, "00"):
+		s = s[2:]
+	default:
+		return "", fmt.Errorf("%w: %q: missing country code", ErrInvalidPhone, in)
+	}
+
+	s = dropTrunkPrefix(s)
+
+	digits := make([]byte, 0, len(s))
+
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case c >= '0' && c <= '9':
+			digits = append(digits, c)
+		case c == ' ' || c == '-' || c == '.' || c == '/' || c == '(' || c == ')':
+		default:
+			return "", fmt.Errorf("%w: %q: unexpected character %q", ErrInvalidPhone, in, c)
+		}
+	}
+
+	if len(digits) < minPhoneDigits || len(digits) > maxPhoneDigits || digits[0] == '0' {
+		return "", fmt.Errorf("%w: %q: invalid number of digits or country code", ErrInvalidPhone, in)
+	}
+
+	return "+" + string(digits), nil
+}
+
+// dropTrunkPrefix removes the "(0)" group following the country code at the start of s.
+func dropTrunkPrefix(s string) string {
+	idx := strings.Index(s, trunkPrefix)
+	if idx < 0 {
+		return s
+	}
+
+	code := strings.TrimRight(s[:idx], " ")
+	if len(code) == 0 || len(code) > maxCountryCodeDigits || strings.Trim(code, "0123456789") != "" {
+		return s
+	}
+
+	return code + s[idx+len(trunkPrefix):]
+}
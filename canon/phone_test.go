@@ -0,0 +1,71 @@
+// MIT License
+//
+// Copyright (c) 2021 Iván Szkiba
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package canon_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/szkiba/nbid/canon"
+)
+
+func TestPhone(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		in      string
+		out     string
+		wantErr bool
+	}{
+		{in: "+36 1 234 5678", out: "+3612345678"},
+		{in: "0036-1-234-5678", out: "+3612345678"},
+		{in: "+1 (555) 010.0199", out: "+15550100199"},
+		{in: "+44 (0)20 7946 0958", out: "+442079460958"},
+		{in: "+44(0)20-7946-0958", out: "+442079460958"},
+		{in: "0044 (0) 20 7946 0958", out: "+442079460958"},
+		{in: "+44 20 7946 0958", out: "+442079460958"},
+		{in: "+44 20 (0)7946 0958", out: "+4420079460958"},
+		{in: "06 1 234 5678", wantErr: true},
+		{in: "+36 1 234 567x", wantErr: true},
+		{in: "+123", wantErr: true},
+		{in: "+1234567890123456", wantErr: true},
+		{in: "+0 123 4567", wantErr: true},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.in, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := canon.Phone(tt.in)
+			if tt.wantErr {
+				assert.True(t, errors.Is(err, canon.ErrInvalidPhone), err)
+
+				return
+			}
+
+			assert.Nil(t, err)
+			assert.Equal(t, tt.out, got)
+		})
+	}
+}
@@ -0,0 +1,223 @@
+// MIT License
+//
+// Copyright (c) 2021 Iván Szkiba
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package canon
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+var defaultPorts = map[string]string{
+	"http":  "80",
+	"https": "443",
+	"ws":    "80",
+	"wss":   "443",
+	"ftp":   "21",
+}
+
+// URL returns the canonical form of an absolute URL.
+//
+// Besides the RFC 3986 syntax based normalization (lower case scheme and host,
+// upper case percent-encoding hex digits, decoding of percent-encoded unreserved
+// characters, removal of dot segments) the following scheme based normalizations
+// are applied:
+//
+//   - the host is canonicalized by Host (so IDN and punycode forms are the same)
+//   - default ports of http, https, ws, wss and ftp are removed
+//   - empty path is replaced by "/", trailing slash of other paths is removed
+//   - query parameters are sorted, empty query is removed
+//   - fragment is removed, as it is not part of the resource identity
+func URL(s string) (string, error) {
+	u, err := url.Parse(strings.TrimSpace(s))
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrInvalidURL, err)
+	}
+
+	if u.Scheme == "" || u.Host == "" {
+		return "", fmt.Errorf("%w: %q: not an absolute URL", ErrInvalidURL, s)
+	}
+
+	scheme := strings.ToLower(u.Scheme)
+
+	host, port := u.Host, ""
+	if h, p, err := net.SplitHostPort(u.Host); err == nil {
+		host, port = h, p
+	}
+
+	host, err = Host(host)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrInvalidURL, err)
+	}
+
+	if strings.Contains(host, ":") {
+		host = "[" + host + "]"
+	}
+
+	if port != "" && port != defaultPorts[scheme] {
+		host += ":" + port
+	}
+
+	var b strings.Builder
+
+	b.WriteString(scheme)
+	b.WriteString("://")
+
+	if u.User != nil {
+		b.WriteString(normalizePercent(u.User.String()))
+		b.WriteByte('@')
+	}
+
+	b.WriteString(host)
+	b.WriteString(canonicalPath(u.EscapedPath()))
+
+	if q := canonicalQuery(u.RawQuery); q != "" {
+		b.WriteByte('?')
+		b.WriteString(q)
+	}
+
+	return b.String(), nil
+}
+
+func canonicalPath(p string) string {
+	p = removeDotSegments(normalizePercent(p))
+
+	if p == "" {
+		return "/"
+	}
+
+	if len(p) > 1 {
+		p = strings.TrimSuffix(p, "/")
+	}
+
+	return p
+}
+
+func canonicalQuery(q string) string {
+	var params []string
+
+	for _, param := range strings.Split(q, "&") {
+		if param != "" {
+			params = append(params, normalizePercent(param))
+		}
+	}
+
+	sort.Strings(params)
+
+	return strings.Join(params, "&")
+}
+
+// removeDotSegments implements RFC 3986 section 5.2.4.
+func removeDotSegments(p string) string {
+	var out []string
+
+	for p != "" {
+		switch {
+		case strings.HasPrefix(p, "../"):
+			p = p[3:]
+		case strings.HasPrefix(p, "./"):
+			p = p[2:]
+		case strings.HasPrefix(p, "/./"):
+			p = p[2:]
+		case p == "/.":
+			p = "/"
+		case strings.HasPrefix(p, "/../"):
+			p = p[3:]
+
+			if len(out) > 0 {
+				out = out[:len(out)-1]
+			}
+		case p == "/..":
+			p = "/"
+
+			if len(out) > 0 {
+				out = out[:len(out)-1]
+			}
+		case p == "." || p == "..":
+			p = ""
+		default:
+			i := strings.IndexByte(p[1:], '/')
+			if i < 0 {
+				out = append(out, p)
+				p = ""
+			} else {
+				out = append(out, p[:i+1])
+				p = p[i+1:]
+			}
+		}
+	}
+
+	return strings.Join(out, "")
+}
+
+// normalizePercent decodes percent-encoded unreserved characters and
+// upper cases the hex digits of the remaining percent-encodings.
+func normalizePercent(s string) string {
+	if !strings.Contains(s, "%") {
+		return s
+	}
+
+	var b strings.Builder
+
+	for i := 0; i < len(s); i++ {
+		if s[i] != '%' || i+2 >= len(s) || !isHex(s[i+1]) || !isHex(s[i+2]) {
+			b.WriteByte(s[i])
+
+			continue
+		}
+
+		c := unhex(s[i+1])<<4 | unhex(s[i+2])
+		if isUnreserved(c) {
+			b.WriteByte(c)
+		} else {
+			b.WriteByte('%')
+			b.WriteString(strings.ToUpper(s[i+1 : i+3]))
+		}
+
+		i += 2
+	}
+
+	return b.String()
+}
+
+func isUnreserved(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9') ||
+		c == '-' || c == '.' || c == '_' || c == '~'
+}
+
+func isHex(c byte) bool {
+	return (c >= '0' && c <= '9') || (c >= 'a' && c <= 'f') || (c >= 'A' && c <= 'F')
+}
+
+func unhex(c byte) byte {
+	switch {
+	case c >= 'a':
+		return c - 'a' + 10 //nolint:gomnd
+	case c >= 'A':
+		return c - 'A' + 10 //nolint:gomnd
+	default:
+		return c - '0'
+	}
+}
@@ -0,0 +1,77 @@
+// MIT License
+//
+// Copyright (c) 2021 Iván Szkiba
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package canon_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/szkiba/nbid/canon"
+)
+
+func TestURL(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		in      string
+		out     string
+		wantErr bool
+	}{
+		{name: "plain", in: "https://example.com/a/b", out: "https://example.com/a/b"},
+		{name: "case", in: "HTTPS://Example.COM/A", out: "https://example.com/A"},
+		{name: "default_port", in: "http://example.com:80/", out: "http://example.com/"},
+		{name: "other_port", in: "http://example.com:8080", out: "http://example.com:8080/"},
+		{name: "empty_path", in: "https://example.com", out: "https://example.com/"},
+		{name: "trailing_slash", in: "https://example.com/a/b/", out: "https://example.com/a/b"},
+		{name: "dot_segments", in: "https://example.com/a/./b/../c", out: "https://example.com/a/c"},
+		{name: "dot_segments_root", in: "https://example.com/../a", out: "https://example.com/a"},
+		{name: "percent", in: "https://example.com/%7euser/%2fx%3a", out: "https://example.com/~user/%2Fx%3A"},
+		{name: "query_order", in: "https://example.com/?b=2&a=1&&a=0", out: "https://example.com/?a=0&a=1&b=2"},
+		{name: "empty_query", in: "https://example.com/?", out: "https://example.com/"},
+		{name: "fragment", in: "https://example.com/a#top", out: "https://example.com/a"},
+		{name: "idn", in: "https://Bücher.example/", out: "https://xn--bcher-kva.example/"},
+		{name: "ipv6", in: "http://[2001:DB8::1]:80/", out: "http://[2001:db8::1]/"},
+		{name: "userinfo", in: "ftp://user@Example.com:21/x", out: "ftp://user@example.com/x"},
+		{name: "relative", in: "/a/b", wantErr: true},
+		{name: "invalid", in: "http://exa mple.com/", wantErr: true},
+		{name: "invalid_host", in: "http://-a.com/", wantErr: true},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := canon.URL(tt.in)
+			if tt.wantErr {
+				assert.True(t, errors.Is(err, canon.ErrInvalidURL), err)
+
+				return
+			}
+
+			assert.Nil(t, err)
+			assert.Equal(t, tt.out, got)
+		})
+	}
+}
@@ -0,0 +1,142 @@
+// MIT License
+//
+// Copyright (c) 2021 Iván Szkiba
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package nbid
+
+import (
+	"fmt"
+)
+
+// CBORTagUUID is the CBOR tag registered for binary UUIDs (RFC 8949 / IANA tag 37).
+// NBID has the same size as UUID, so it may be used with MarshalCBORTag
+// where consumers expect tagged 16 byte identifiers.
+const CBORTagUUID = 37
+
+const (
+	cborMajorBytes = 2
+	cborMajorText  = 3
+	cborMajorTag   = 6
+
+	cborInfoUint8  = 24
+	cborInfoUint16 = 25
+	cborInfoUint32 = 26
+	cborInfoUint64 = 27
+)
+
+// MarshalCBOR implements the github.com/fxamacker/cbor Marshaler interface.
+// NBID is encoded as a 16 byte long untagged byte string.
+func (id NBID) MarshalCBOR() ([]byte, error) {
+	return append([]byte{cborMajorBytes<<5 | rawLen}, id[:]...), nil
+}
+
+// MarshalCBORTag encodes NBID as a 16 byte long byte string wrapped in the given tag.
+func (id NBID) MarshalCBORTag(tag uint64) ([]byte, error) {
+	b := appendCBORHead(nil, cborMajorTag, tag)
+
+	return append(append(b, cborMajorBytes<<5|rawLen), id[:]...), nil
+}
+
+// UnmarshalCBOR implements the github.com/fxamacker/cbor Unmarshaler interface.
+// It accepts a 16 byte long byte string or a 26 character long text string
+// (the string form), optionally wrapped in a single tag of any number.
+func (id *NBID) UnmarshalCBOR(data []byte) error {
+	major, arg, rest, err := readCBORHead(data)
+	if err != nil {
+		return err
+	}
+
+	if major == cborMajorTag {
+		if major, arg, rest, err = readCBORHead(rest); err != nil {
+			return err
+		}
+	}
+
+	if uint64(len(rest)) != arg {
+		return fmt.Errorf("%w: CBOR length mismatch", ErrInvalidID)
+	}
+
+	switch major {
+	case cborMajorBytes:
+		return id.UnmarshalBinary(rest)
+	case cborMajorText:
+		return id.UnmarshalText(rest)
+	default:
+		return fmt.Errorf("%w: unexpected CBOR major type %d", ErrInvalidID, major)
+	}
+}
+
+func appendCBORHead(b []byte, major byte, arg uint64) []byte {
+	switch {
+	case arg < cborInfoUint8:
+		return append(b, major<<5|byte(arg))
+	case arg <= 0xff:
+		return append(b, major<<5|cborInfoUint8, byte(arg))
+	case arg <= 0xffff:
+		return append(b, major<<5|cborInfoUint16, byte(arg>>8), byte(arg))
+	case arg <= 0xffffffff:
+		return append(b, major<<5|cborInfoUint32, byte(arg>>24), byte(arg>>16), byte(arg>>8), byte(arg))
+	default:
+		b = append(b, major<<5|cborInfoUint64)
+
+		for shift := 56; shift >= 0; shift -= 8 {
+			b = append(b, byte(arg>>uint(shift)))
+		}
+
+		return b
+	}
+}
+
+func readCBORHead(data []byte) (major byte, arg uint64, rest []byte, err error) {
+	if len(data) == 0 {
+		return 0, 0, nil, fmt.Errorf("%w: empty CBOR data", ErrInvalidID)
+	}
+
+	major, info := data[0]>>5, data[0]&0x1f
+	data = data[1:]
+
+	var size int
+
+	switch {
+	case info < cborInfoUint8:
+		return major, uint64(info), data, nil
+	case info == cborInfoUint8:
+		size = 1
+	case info == cborInfoUint16:
+		size = 2
+	case info == cborInfoUint32:
+		size = 4
+	case info == cborInfoUint64:
+		size = 8
+	default:
+		return 0, 0, nil, fmt.Errorf("%w: unsupported CBOR additional info %d", ErrInvalidID, info)
+	}
+
+	if len(data) < size {
+		return 0, 0, nil, fmt.Errorf("%w: truncated CBOR data", ErrInvalidID)
+	}
+
+	for _, b := range data[:size] {
+		arg = arg<<8 | uint64(b)
+	}
+
+	return major, arg, data[size:], nil
+}
@@ -0,0 +1,93 @@
+// MIT License
+//
+// Copyright (c) 2021 Iván Szkiba
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package nbid_test
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/szkiba/nbid"
+)
+
+const (
+	sampleText = "QUKFNCO7QU098QEAJAUB021E9S"
+	sampleHex  = "d7a8fbb307d7809469ca9abcb0082e4f"
+)
+
+func TestCBOR(t *testing.T) {
+	t.Parallel()
+
+	id := nbid.MustParse(sampleText)
+
+	b, err := id.MarshalCBOR()
+	assert.Nil(t, err)
+	assert.Equal(t, "50"+sampleHex, hex.EncodeToString(b))
+
+	b, err = id.MarshalCBORTag(nbid.CBORTagUUID)
+	assert.Nil(t, err)
+	assert.Equal(t, "d82550"+sampleHex, hex.EncodeToString(b))
+
+	b, err = id.MarshalCBORTag(1000)
+	assert.Nil(t, err)
+	assert.Equal(t, "d903e850"+sampleHex, hex.EncodeToString(b))
+
+	tests := []struct {
+		name    string
+		hex     string
+		wantErr bool
+	}{
+		{name: "bytes", hex: "50" + sampleHex},
+		{name: "tagged", hex: "d82550" + sampleHex},
+		{name: "tagged_long", hex: "d903e850" + sampleHex},
+		{name: "text", hex: "781a" + hex.EncodeToString([]byte(sampleText))},
+		{name: "bytes_uint8_len", hex: "5810" + sampleHex},
+		{name: "empty", hex: "", wantErr: true},
+		{name: "short", hex: "50d7a8", wantErr: true},
+		{name: "wrong_size", hex: "43010203", wantErr: true},
+		{name: "integer", hex: "1864", wantErr: true},
+		{name: "truncated_head", hex: "59", wantErr: true},
+		{name: "indefinite", hex: "5f", wantErr: true},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			data, err := hex.DecodeString(tt.hex)
+			assert.Nil(t, err)
+
+			var got nbid.NBID
+
+			err = got.UnmarshalCBOR(data)
+			if tt.wantErr {
+				assert.Error(t, err)
+
+				return
+			}
+
+			assert.Nil(t, err)
+			assert.Equal(t, id, got)
+		})
+	}
+}
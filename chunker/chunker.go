@@ -0,0 +1,286 @@
+// MIT License
+//
+// Copyright (c) 2021 Iván Szkiba
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Package chunker splits content into variable size chunks identified by NBIDs.
+//
+// Chunk boundaries are content defined, found by the FastCDC algorithm
+// (gear rolling hash with normalized chunking), so inserting or removing
+// bytes only changes the chunks around the edit. This makes chunks suitable
+// for deduplication of similar content.
+//
+// The ID of a chunk is the NBID of its content. The ID of the whole content is
+// derived from the chunk list:
+//
+//	nbid.NewFields(Namespace, size1, id1, size2, id2, ...)
+//
+// where sizes are unsigned integers. The gear table of the rolling hash is
+// derived from SHA256 (see the source), so the same content and options always
+// give the same chunks on every platform.
+package chunker
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"math/bits"
+
+	"github.com/szkiba/nbid"
+)
+
+// Namespace is the first field of the encoding of chunk lists.
+const Namespace = "github.com/szkiba/nbid/chunker"
+
+// Default chunk sizes.
+const (
+	DefaultMin = 16 << 10
+	DefaultAvg = 64 << 10
+	DefaultMax = 256 << 10
+
+	minSize = 64
+	window  = minSize - 1 // preceding bytes priming the gear hash
+)
+
+// ErrInvalidOptions is returned for chunk sizes out of order or too small.
+var ErrInvalidOptions = errors.New("chunker: invalid options")
+
+// Options contains the chunk size limits, zero values are replaced by defaults.
+type Options struct {
+	// Min is the minimum chunk size (except the last chunk).
+	Min int
+	// Avg is the expected chunk size, rounded to power of two.
+	Avg int
+	// Max is the maximum chunk size.
+	Max int
+}
+
+// Chunk is a content defined chunk.
+type Chunk struct {
+	// Offset of the chunk in the content.
+	Offset int64
+	// Size of the chunk.
+	Size int
+	// ID of the chunk, the NBID of Data.
+	ID nbid.NBID
+	// Data of the chunk, valid until the next call of Next.
+	Data []byte
+}
+
+// Chunker splits the content of an io.Reader into chunks.
+type Chunker struct {
+	r      io.Reader
+	opts   Options
+	maskS  uint64
+	maskL  uint64
+	buf    []byte
+	start  int
+	end    int
+	eof    bool
+	offset int64
+	list   hash.Hash // running hash of the chunk list encoding
+	enc    []byte
+}
+
+// New returns a Chunker reading content from r.
+func New(r io.Reader, opts Options) (*Chunker, error) {
+	if opts.Min == 0 {
+		opts.Min = DefaultMin
+	}
+
+	if opts.Avg == 0 {
+		opts.Avg = DefaultAvg
+	}
+
+	if opts.Max == 0 {
+		opts.Max = DefaultMax
+	}
+
+	if opts.Min < minSize || opts.Min > opts.Avg || opts.Avg > opts.Max {
+		return nil, fmt.Errorf("%w: min %d, avg %d, max %d", ErrInvalidOptions, opts.Min, opts.Avg, opts.Max)
+	}
+
+	b := bits.Len(uint(opts.Avg)) - 1
+	if opts.Avg-(1<<b) > (1<<(b+1))-opts.Avg {
+		b++
+	}
+
+	c := &Chunker{
+		r:     r,
+		opts:  opts,
+		maskS: mask(b + 1),
+		maskL: mask(b - 1),
+		buf:   make([]byte, 2*opts.Max), //nolint:gomnd
+		list:  sha256.New(),
+	}
+
+	c.enc, _ = nbid.AppendFields(c.enc[:0], Namespace)
+	c.list.Write(c.enc) //nolint:errcheck
+
+	return c, nil
+}
+
+// Next returns the next chunk, or io.EOF after the last chunk.
+func (c *Chunker) Next() (*Chunk, error) {
+	if err := c.fill(); err != nil {
+		return nil, err
+	}
+
+	if c.start == c.end {
+		return nil, io.EOF
+	}
+
+	data := c.buf[c.start:c.end]
+	data = data[:c.cut(data)]
+
+	chunk := &Chunk{Offset: c.offset, Size: len(data), ID: nbid.New(data), Data: data}
+
+	c.start += len(data)
+	c.offset += int64(len(data))
+	c.enc, _ = nbid.AppendFields(c.enc[:0], uint64(len(data)), chunk.ID)
+	c.list.Write(c.enc) //nolint:errcheck
+
+	return chunk, nil
+}
+
+// ID returns the ID of the chunks returned so far, the ID of the whole content after io.EOF.
+func (c *Chunker) ID() nbid.NBID {
+	id, _ := nbid.FromBytes(c.list.Sum(nil)[:16])
+
+	return id
+}
+
+// fill reads until the buffer contains at least Max bytes or EOF is reached.
+func (c *Chunker) fill() error {
+	if c.eof || c.end-c.start >= c.opts.Max {
+		return nil
+	}
+
+	c.end = copy(c.buf, c.buf[c.start:c.end])
+	c.start = 0
+
+	for c.end < len(c.buf) {
+		n, err := c.r.Read(c.buf[c.end:])
+		c.end += n
+
+		if errors.Is(err, io.EOF) {
+			c.eof = true
+
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// cut returns the length of the chunk at the beginning of data.
+func (c *Chunker) cut(data []byte) int {
+	n := len(data)
+	if n <= c.opts.Min {
+		return n
+	}
+
+	if n > c.opts.Max {
+		n = c.opts.Max
+	}
+
+	normal := c.opts.Avg
+	if normal > n {
+		normal = n
+	}
+
+	var h uint64
+
+	// The chunk length is i+1, so the first candidate boundary gives a chunk of Min bytes.
+	// The hash is primed with the preceding window, so the candidate is as likely as any other.
+	i := c.opts.Min - 1
+
+	for j := i - window; j < i; j++ {
+		h = (h << 1) + gear[data[j]]
+	}
+
+	for ; i < normal; i++ {
+		h = (h << 1) + gear[data[i]]
+		if h&c.maskS == 0 {
+			return i + 1
+		}
+	}
+
+	for ; i < n; i++ {
+		h = (h << 1) + gear[data[i]]
+		if h&c.maskL == 0 {
+			return i + 1
+		}
+	}
+
+	return n
+}
+
+// Split returns all chunks of the content of r and the ID of the content.
+// The Data field of the returned chunks is nil.
+func Split(r io.Reader, opts Options) ([]Chunk, nbid.NBID, error) {
+	c, err := New(r, opts)
+	if err != nil {
+		return nil, nbid.Nil, err
+	}
+
+	var chunks []Chunk
+
+	for {
+		chunk, err := c.Next()
+		if errors.Is(err, io.EOF) {
+			return chunks, c.ID(), nil
+		}
+
+		if err != nil {
+			return nil, nbid.Nil, err
+		}
+
+		chunk.Data = nil
+		chunks = append(chunks, *chunk)
+	}
+}
+
+// mask returns a mask of the n most significant bits, the bits influenced by the most bytes.
+func mask(n int) uint64 {
+	if n <= 0 {
+		return 0
+	}
+
+	return ^uint64(0) << (64 - n) //nolint:gomnd
+}
+
+// gear is the table of the gear rolling hash, gear[i] is the first 8 bytes
+// (big endian) of the SHA256 hash of Namespace followed by the byte i.
+var gear = func() (g [256]uint64) {
+	for i := range g {
+		sum := sha256.Sum256(append([]byte(Namespace), byte(i)))
+		g[i] = binary.BigEndian.Uint64(sum[:])
+	}
+
+	return g
+}()
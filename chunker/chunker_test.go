@@ -0,0 +1,192 @@
+// MIT License
+//
+// Copyright (c) 2021 Iván Szkiba
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package chunker_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"math/rand"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/szkiba/nbid"
+	"github.com/szkiba/nbid/chunker"
+)
+
+var opts = chunker.Options{Min: 256, Avg: 1024, Max: 4096}
+
+func testData(size int, seed int64) []byte {
+	data := make([]byte, size)
+
+	rand.New(rand.NewSource(seed)).Read(data) //nolint:gosec
+
+	return data
+}
+
+func TestChunker(t *testing.T) {
+	t.Parallel()
+
+	data := testData(1<<20, 1)
+
+	c, err := chunker.New(bytes.NewReader(data), opts)
+	assert.Nil(t, err)
+
+	var (
+		joined []byte
+		count  int
+		offset int64
+	)
+
+	for {
+		chunk, err := c.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		assert.Nil(t, err)
+		assert.Equal(t, offset, chunk.Offset)
+		assert.Equal(t, len(chunk.Data), chunk.Size)
+		assert.Equal(t, nbid.New(chunk.Data), chunk.ID)
+		assert.LessOrEqual(t, chunk.Size, opts.Max)
+
+		if chunk.Offset+int64(chunk.Size) < int64(len(data)) {
+			assert.GreaterOrEqual(t, chunk.Size, opts.Min)
+		}
+
+		joined = append(joined, chunk.Data...)
+		offset += int64(chunk.Size)
+		count++
+	}
+
+	assert.Equal(t, data, joined)
+
+	avg := len(data) / count
+
+	assert.Greater(t, avg, opts.Avg/2)
+	assert.Less(t, avg, opts.Avg*2)
+
+	chunks, id, err := chunker.Split(iotest.OneByteReader(bytes.NewReader(data)), opts)
+
+	assert.Nil(t, err)
+	assert.Len(t, chunks, count)
+	assert.Equal(t, c.ID(), id)
+
+	fields := []interface{}{chunker.Namespace}
+	for _, chunk := range chunks {
+		assert.Nil(t, chunk.Data)
+
+		fields = append(fields, uint64(chunk.Size), chunk.ID)
+	}
+
+	assert.Equal(t, nbid.MustNewFields(fields...), id)
+}
+
+func TestChunkerStable(t *testing.T) {
+	t.Parallel()
+
+	chunks, id, err := chunker.Split(bytes.NewReader(testData(1<<16, 3)), opts)
+
+	assert.Nil(t, err)
+	assert.Len(t, chunks, 57)
+	assert.Equal(t, 1399, chunks[0].Size)
+	assert.Equal(t, "JTUBUM9LNS4FNREOHUEHPLMVUK", chunks[0].ID.String())
+	assert.Equal(t, "1G0G0PP38KGMT6CB1J940CBOSG", id.String())
+}
+
+func TestChunkerMin(t *testing.T) {
+	t.Parallel()
+
+	o := chunker.Options{Min: 64, Avg: 128, Max: 256}
+
+	chunks, _, err := chunker.Split(bytes.NewReader(testData(1<<18, 4)), o)
+	assert.Nil(t, err)
+
+	smallest := o.Max
+
+	for _, chunk := range chunks[:len(chunks)-1] {
+		if chunk.Size < smallest {
+			smallest = chunk.Size
+		}
+	}
+
+	assert.Equal(t, o.Min, smallest)
+}
+
+func TestChunkerShift(t *testing.T) {
+	t.Parallel()
+
+	data := testData(1<<18, 2)
+
+	before, _, err := chunker.Split(bytes.NewReader(data), opts)
+	assert.Nil(t, err)
+
+	edited := append([]byte("inserted"), data...)
+
+	after, _, err := chunker.Split(bytes.NewReader(edited), opts)
+	assert.Nil(t, err)
+
+	ids := make(map[nbid.NBID]bool)
+	for _, chunk := range before {
+		ids[chunk.ID] = true
+	}
+
+	shared := 0
+
+	for _, chunk := range after {
+		if ids[chunk.ID] {
+			shared++
+		}
+	}
+
+	assert.GreaterOrEqual(t, shared, len(before)-2)
+}
+
+func TestChunkerSmall(t *testing.T) {
+	t.Parallel()
+
+	chunks, id, err := chunker.Split(bytes.NewReader(nil), chunker.Options{})
+
+	assert.Nil(t, err)
+	assert.Empty(t, chunks)
+	assert.Equal(t, nbid.MustNewFields(chunker.Namespace), id)
+
+	chunks, _, err = chunker.Split(bytes.NewReader([]byte("hello")), chunker.Options{})
+
+	assert.Nil(t, err)
+	assert.Len(t, chunks, 1)
+	assert.Equal(t, nbid.New([]byte("hello")), chunks[0].ID)
+}
+
+func TestChunkerErrors(t *testing.T) {
+	t.Parallel()
+
+	for _, o := range []chunker.Options{{Min: 10}, {Min: 2048, Avg: 1024}, {Avg: 1 << 20, Max: 1 << 19}} {
+		_, err := chunker.New(bytes.NewReader(nil), o)
+		assert.True(t, errors.Is(err, chunker.ErrInvalidOptions), "%v", o)
+	}
+
+	_, _, err := chunker.Split(iotest.ErrReader(io.ErrUnexpectedEOF), opts)
+	assert.True(t, errors.Is(err, io.ErrUnexpectedEOF))
+}
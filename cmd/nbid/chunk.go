@@ -0,0 +1,98 @@
+// MIT License
+//
+// Copyright (c) 2021 Iván Szkiba
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/szkiba/nbid/chunker"
+)
+
+const chunkUsage = `usage: nbid chunk [-min size] [-avg size] [-max size] <file>
+
+Split file into content defined chunks and print offset, size and NBID of
+every chunk, followed by the NBID of the chunk list and the file name.
+
+`
+
+var errMissingFile = errors.New("missing file argument")
+
+func chunk(args []string, stdout io.Writer) error {
+	flags := flag.NewFlagSet(args[0], flag.ContinueOnError)
+
+	flags.Usage = func() {
+		fmt.Fprint(flags.Output(), chunkUsage)
+		flags.PrintDefaults()
+	}
+
+	var opts chunker.Options
+
+	flags.IntVar(&opts.Min, "min", chunker.DefaultMin, "minimum chunk size in bytes")
+	flags.IntVar(&opts.Avg, "avg", chunker.DefaultAvg, "average chunk size in bytes")
+	flags.IntVar(&opts.Max, "max", chunker.DefaultMax, "maximum chunk size in bytes")
+
+	if err := flags.Parse(args[1:]); err != nil {
+		return err
+	}
+
+	if flags.NArg() != 1 {
+		flags.Usage()
+
+		return errMissingFile
+	}
+
+	file, err := os.Open(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	defer file.Close() //nolint:errcheck
+
+	c, err := chunker.New(file, opts)
+	if err != nil {
+		return err
+	}
+
+	for {
+		ch, err := c.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if _, err := fmt.Fprintf(stdout, "%d %d %s\n", ch.Offset, ch.Size, ch.ID); err != nil {
+			return err
+		}
+	}
+
+	_, err = fmt.Fprintf(stdout, "%s  %s\n", c.ID(), flags.Arg(0))
+
+	return err
+}
@@ -0,0 +1,65 @@
+// MIT License
+//
+// Copyright (c) 2021 Iván Szkiba
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/szkiba/nbid/chunker"
+)
+
+func Test_chunk(t *testing.T) {
+	t.Parallel()
+
+	data := bytes.Repeat([]byte("The quick brown fox jumps over the lazy dog\n"), 1000)
+	name := filepath.Join(t.TempDir(), "fox.txt")
+
+	assert.Nil(t, os.WriteFile(name, data, 0o600))
+
+	chunks, id, err := chunker.Split(bytes.NewReader(data), chunker.Options{Min: 1024, Avg: 4096, Max: 16384})
+	assert.Nil(t, err)
+
+	var buf bytes.Buffer
+
+	assert.Nil(t, chunk([]string{"chunk", "-min", "1024", "-avg", "4096", "-max", "16384", name}, &buf))
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+
+	assert.Len(t, lines, len(chunks)+1)
+
+	for i, c := range chunks {
+		assert.Equal(t, fmt.Sprintf("%d %d %s", c.Offset, c.Size, c.ID), lines[i])
+	}
+
+	assert.Equal(t, id.String()+"  "+name, lines[len(chunks)])
+
+	assert.Error(t, chunk([]string{"chunk"}, &buf))
+	assert.Error(t, chunk([]string{"chunk", filepath.Join(t.TempDir(), "missing")}, &buf))
+	assert.Error(t, chunk([]string{"chunk", "-min", "1", name}, &buf))
+}
@@ -23,8 +23,10 @@
 package main
 
 import (
+	"errors"
 	"flag"
 	"fmt"
+	"io"
 	"os"
 	"runtime"
 
@@ -34,39 +36,78 @@ import (
 var version = "dev"
 
 type options struct {
-	version bool
-	input   string
+	version   bool
+	template  bool
+	json      bool
+	normalize string
+	input     string
 }
 
 const usage = `usage: %s [name]
+       %s <command> [arguments]
 
 Generate NBID for name, or random NBID if name is missing.
 
 Example: %s "The quick brown fox jumps over the lazy dog"
 Output: QUKFNCO7QU098QEAJAUB021E9S
 
+Commands:
+  chunk     print content defined chunks of file with their NBIDs
+  schema    print JSON Schema or OpenAPI definition of NBID
+  sum       print or verify NBIDs of files in sha256sum layout
+  tree      print Merkle tree NBID of directory
+
 `
 
+// command is a subcommand of the CLI. args[0] is the name of the command.
+type command func(args []string, stdout io.Writer) error
+
+var commands = map[string]command{
+	"chunk":  chunk,
+	"schema": schema,
+	"sum":    sum,
+	"tree":   treeCmd,
+}
+
 func getopt(args []string) *options {
 	flags := flag.NewFlagSet(args[0], flag.ExitOnError)
 
 	flags.Usage = func() {
-		fmt.Fprintf(flags.Output(), usage, flags.Name(), flags.Name())
+		fmt.Fprintf(flags.Output(), usage, flags.Name(), flags.Name(), flags.Name())
 		flags.PrintDefaults()
 	}
 
 	o := options{}
 
 	ver := flags.Bool("v", false, "prints version")
+	tmpl := flags.Bool("template", false, "renders template from stdin using NBID template functions")
+	jsonMode := flags.Bool("json", false,
+		"generates NBID of canonical (RFC 8785) form of JSON name, or JSON from stdin if name is missing")
+	normalize := flags.String("normalize", "",
+		"normalizes name before hashing, comma separated list of nfc, nfkc, fold, trim, collapse")
 
 	_ = flags.Parse(args[1:])
 
 	o.version = *ver
+	o.template = *tmpl
+	o.json = *jsonMode
+	o.normalize = *normalize
 	o.input = flags.Arg(0)
 
 	return &o
 }
 
+var errNormalizeMode = errors.New("-normalize cannot be used with -json or -template")
+
+// check reports conflicting options.
+func (o *options) check() error {
+	if o.normalize != "" && (o.json || o.template) {
+		return errNormalizeMode
+	}
+
+	return nil
+}
+
 func getid(s string) string {
 	if s == "" {
 		return nbid.Random().String()
@@ -75,16 +116,97 @@ func getid(s string) string {
 	return nbid.New([]byte(s)).String()
 }
 
+// getjsonid returns the NBID of the JSON document s, or read from in if s is empty.
+func getjsonid(in io.Reader, s string) (string, error) {
+	data := []byte(s)
+
+	if s == "" {
+		var err error
+
+		if data, err = io.ReadAll(in); err != nil {
+			return "", err
+		}
+	}
+
+	id, err := nbid.NewJSON(data)
+	if err != nil {
+		return "", err
+	}
+
+	return id.String(), nil
+}
+
+// getnormid returns the NBID of s normalized according to spec, or random NBID if s is empty.
+// A non-empty s is never treated as missing, even if it normalizes to empty string.
+func getnormid(s string, spec string) (string, error) {
+	normalized, err := normalize(s, spec)
+	if err != nil {
+		return "", err
+	}
+
+	if s == "" {
+		return getid(""), nil
+	}
+
+	return nbid.New([]byte(normalized)).String(), nil
+}
+
+// normalize returns s normalized according to spec (see nbid.ParseNormalizer).
+func normalize(s string, spec string) (string, error) {
+	n, err := nbid.ParseNormalizer(spec)
+	if err != nil {
+		return "", err
+	}
+
+	return n.Normalize(s), nil
+}
+
 func getver() string {
 	return fmt.Sprintf("nbid/%s %s/%s", version, runtime.GOOS, runtime.GOARCH)
 }
 
 func main() {
+	if len(os.Args) > 1 {
+		if cmd, ok := commands[os.Args[1]]; ok {
+			if err := cmd(os.Args[1:], os.Stdout); err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				os.Exit(1)
+			}
+
+			return
+		}
+	}
+
 	o := getopt(os.Args)
 
-	if o.version {
+	if err := o.check(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	switch {
+	case o.version:
 		fmt.Fprintln(os.Stderr, getver())
-	} else {
-		fmt.Fprintln(os.Stdout, getid(o.input))
+	case o.template:
+		if err := render(os.Stdin, os.Stdout, o.input); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	case o.json:
+		id, err := getjsonid(os.Stdin, o.input)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+
+		fmt.Fprintln(os.Stdout, id)
+	default:
+		id, err := getnormid(o.input, o.normalize)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+
+		fmt.Fprintln(os.Stdout, id)
 	}
 }
@@ -48,6 +48,21 @@ func Test_getopt(t *testing.T) {
 			want: &options{version: true},
 			args: []string{"-v"},
 		},
+		{
+			name: "template",
+			want: &options{template: true, input: "foo"},
+			args: []string{"--template", "foo"},
+		},
+		{
+			name: "json",
+			want: &options{json: true, input: "{}"},
+			args: []string{"--json", "{}"},
+		},
+		{
+			name: "normalize",
+			want: &options{normalize: "nfc,fold", input: "Foo"},
+			args: []string{"--normalize", "nfc,fold", "Foo"},
+		},
 	}
 	for _, tt := range tests {
 		tt := tt
@@ -72,6 +87,73 @@ func Test_getid(t *testing.T) {
 	assert.NotEqual(t, id1, id2)
 }
 
+func Test_getjsonid(t *testing.T) {
+	t.Parallel()
+
+	a, err := getjsonid(strings.NewReader(""), `{"b":1,"a":[true]}`)
+	assert.Nil(t, err)
+
+	b, err := getjsonid(strings.NewReader(`{ "a": [ true ], "b": 1.0 }`), "")
+	assert.Nil(t, err)
+
+	assert.Equal(t, a, b)
+	assert.Equal(t, getid(`{"a":[true],"b":1}`), a)
+
+	_, err = getjsonid(strings.NewReader(""), `{"a":1,"a":2}`)
+	assert.Error(t, err)
+}
+
+func Test_normalize(t *testing.T) {
+	t.Parallel()
+
+	s, err := normalize("  THE QUICK BROWN FOX JUMPS OVER THE LAZY DOG", "fold,trim")
+	assert.Nil(t, err)
+	assert.Equal(t, "0N3E13OTJVDFK0OKFV5OV0NH4G", getid(s))
+
+	s, err = normalize(" Foo ", "")
+	assert.Nil(t, err)
+	assert.Equal(t, " Foo ", s)
+
+	_, err = normalize("Foo", "bogus")
+	assert.Error(t, err)
+}
+
+func Test_getnormid(t *testing.T) {
+	t.Parallel()
+
+	id, err := getnormid("  THE QUICK BROWN FOX JUMPS OVER THE LAZY DOG", "fold,trim")
+	assert.Nil(t, err)
+	assert.Equal(t, "0N3E13OTJVDFK0OKFV5OV0NH4G", id)
+
+	// whitespace only name is not missing
+	a, err := getnormid("   ", "trim")
+	assert.Nil(t, err)
+
+	b, err := getnormid("\t", "trim")
+	assert.Nil(t, err)
+	assert.Equal(t, a, b)
+	assert.Equal(t, "SEOC8GKOVGE196NRUJ49IRTP4G", a) // NBID of empty string
+
+	r1, err := getnormid("", "trim")
+	assert.Nil(t, err)
+
+	r2, err := getnormid("", "trim")
+	assert.Nil(t, err)
+	assert.NotEqual(t, r1, r2)
+
+	_, err = getnormid("", "bogus")
+	assert.Error(t, err)
+}
+
+func Test_check(t *testing.T) {
+	t.Parallel()
+
+	assert.Nil(t, (&options{normalize: "nfc"}).check())
+	assert.Nil(t, (&options{json: true}).check())
+	assert.ErrorIs(t, (&options{normalize: "nfc", json: true}).check(), errNormalizeMode)
+	assert.ErrorIs(t, (&options{normalize: "nfc", template: true}).check(), errNormalizeMode)
+}
+
 func Test_getver(t *testing.T) {
 	t.Parallel()
 
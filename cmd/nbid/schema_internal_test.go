@@ -0,0 +1,60 @@
+// MIT License
+//
+// Copyright (c) 2021 Iván Szkiba
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/szkiba/nbid"
+)
+
+func Test_schema(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	assert.Nil(t, schema([]string{"schema"}, &buf))
+
+	var s struct {
+		Schema  string `json:"$schema"`
+		Type    string `json:"type"`
+		Pattern string `json:"pattern"`
+	}
+
+	assert.Nil(t, json.Unmarshal(buf.Bytes(), &s))
+	assert.Equal(t, nbid.JSONSchemaDialect, s.Schema)
+	assert.Equal(t, "string", s.Type)
+	assert.True(t, regexp.MustCompile(s.Pattern).MatchString(getid("")))
+
+	buf.Reset()
+
+	assert.Nil(t, schema([]string{"schema", "-openapi"}, &buf))
+	assert.Contains(t, buf.String(), `"components"`)
+	assert.NotContains(t, buf.String(), `"$schema"`)
+
+	assert.Error(t, schema([]string{"schema", "-unknown"}, &buf))
+}
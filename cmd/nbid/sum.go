@@ -0,0 +1,190 @@
+// MIT License
+//
+// Copyright (c) 2021 Iván Szkiba
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/szkiba/nbid"
+	"github.com/szkiba/nbid/manifest"
+)
+
+const sumUsage = `usage: nbid sum [-r] [file...]
+       nbid sum -c manifest
+
+Print NBID of file contents in sha256sum layout, or verify files listed in manifest with -c.
+With no file, or when file or manifest is -, read standard input.
+
+`
+
+var (
+	errCheckFailed = errors.New("verification failed")
+	errSumFailed   = errors.New("some files could not be read")
+)
+
+// osFS opens files by their operating system path.
+type osFS struct{}
+
+func (osFS) Open(name string) (fs.File, error) {
+	return os.Open(name)
+}
+
+func sum(args []string, stdout io.Writer) error {
+	flags := flag.NewFlagSet(args[0], flag.ContinueOnError)
+
+	flags.Usage = func() {
+		fmt.Fprint(flags.Output(), sumUsage)
+		flags.PrintDefaults()
+	}
+
+	check := flags.String("c", "", "read NBIDs from manifest and verify files")
+	recursive := flags.Bool("r", false, "process files of directories recursively")
+
+	if err := flags.Parse(args[1:]); err != nil {
+		return err
+	}
+
+	if *check != "" {
+		return sumCheck(*check, os.Stdin, stdout)
+	}
+
+	names := flags.Args()
+	if len(names) == 0 {
+		names = []string{"-"}
+	}
+
+	s := &summer{stdin: os.Stdin, stdout: stdout, stderr: flags.Output()}
+
+	for _, name := range names {
+		s.path(name, *recursive)
+	}
+
+	if s.failed != 0 {
+		return fmt.Errorf("%w (%d errors)", errSumFailed, s.failed)
+	}
+
+	return nil
+}
+
+// summer prints manifest lines of files, reporting errors and continuing like sha256sum.
+type summer struct {
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+	failed int
+}
+
+func (s *summer) report(err error) {
+	fmt.Fprintln(s.stderr, err)
+
+	s.failed++
+}
+
+func (s *summer) path(name string, recursive bool) {
+	if name == "-" {
+		id, err := nbid.NewReader(s.stdin)
+		if err != nil {
+			s.report(err)
+
+			return
+		}
+
+		if _, err = fmt.Fprintln(s.stdout, manifest.Entry{ID: id, Path: name}); err != nil {
+			s.report(err)
+		}
+
+		return
+	}
+
+	if !recursive {
+		s.file(name)
+
+		return
+	}
+
+	_ = filepath.WalkDir(name, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			s.report(err)
+		} else if d.Type().IsRegular() {
+			s.file(path)
+		}
+
+		return nil
+	})
+}
+
+func (s *summer) file(name string) {
+	m, err := manifest.New(osFS{}, name)
+	if err == nil {
+		_, err = m.WriteTo(s.stdout)
+	}
+
+	if err != nil {
+		s.report(err)
+	}
+}
+
+// sumCheck verifies the files listed in the manifest read from path, or from stdin if path is "-".
+func sumCheck(path string, stdin io.Reader, stdout io.Writer) error {
+	in := stdin
+
+	if path != "-" {
+		file, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+
+		defer file.Close() //nolint:errcheck
+
+		in = file
+	}
+
+	m, err := manifest.Parse(in)
+	if err != nil {
+		return err
+	}
+
+	failed := 0
+
+	for _, r := range m.Check(osFS{}) {
+		if r.Status != manifest.OK {
+			failed++
+		}
+
+		if _, err := fmt.Fprintf(stdout, "%s: %s\n", r.Entry.Path, r.Status); err != nil {
+			return err
+		}
+	}
+
+	if failed != 0 {
+		return fmt.Errorf("%w: %d of %d files", errCheckFailed, failed, len(m))
+	}
+
+	return nil
+}
@@ -0,0 +1,97 @@
+// MIT License
+//
+// Copyright (c) 2021 Iván Szkiba
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package main
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_sum(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	fox := filepath.Join(dir, "fox.txt")
+	empty := filepath.Join(dir, "sub", "empty.txt")
+
+	assert.Nil(t, os.WriteFile(fox, []byte("The quick brown fox jumps over the lazy dog"), 0o600))
+	assert.Nil(t, os.Mkdir(filepath.Join(dir, "sub"), 0o700))
+	assert.Nil(t, os.WriteFile(empty, nil, 0o600))
+
+	var buf bytes.Buffer
+
+	assert.Nil(t, sum([]string{"sum", fox}, &buf))
+	assert.Equal(t, "QUKFNCO7QU098QEAJAUB021E9S  "+fox+"\n", buf.String())
+
+	buf.Reset()
+
+	// errors are reported and the remaining files are processed
+	err := sum([]string{"sum", dir, filepath.Join(dir, "missing"), fox}, &buf)
+
+	assert.True(t, errors.Is(err, errSumFailed))
+	assert.Contains(t, err.Error(), "2 errors")
+	assert.Equal(t, "QUKFNCO7QU098QEAJAUB021E9S  "+fox+"\n", buf.String())
+
+	buf.Reset()
+
+	assert.Nil(t, sum([]string{"sum", "-r", dir}, &buf))
+
+	want := []byte("QUKFNCO7QU098QEAJAUB021E9S  " + fox + "\n" + "SEOC8GKOVGE196NRUJ49IRTP4G  " + empty + "\n")
+
+	assert.Equal(t, string(want), buf.String())
+
+	manifest := filepath.Join(t.TempDir(), "NBIDSUMS")
+
+	assert.Nil(t, os.WriteFile(manifest, buf.Bytes(), 0o600))
+
+	buf.Reset()
+
+	assert.Nil(t, sum([]string{"sum", "-c", manifest}, &buf))
+	assert.Equal(t, fox+": OK\n"+empty+": OK\n", buf.String())
+
+	buf.Reset()
+
+	// manifest from standard input
+	assert.Nil(t, sumCheck("-", bytes.NewReader(want), &buf))
+	assert.Equal(t, fox+": OK\n"+empty+": OK\n", buf.String())
+
+	assert.Nil(t, os.WriteFile(fox, []byte("The quick brown fox jumps over the lazy cat"), 0o600))
+	assert.Nil(t, os.Remove(empty))
+
+	buf.Reset()
+
+	err = sum([]string{"sum", "-c", manifest}, &buf)
+
+	assert.True(t, errors.Is(err, errCheckFailed))
+	assert.Contains(t, err.Error(), "2 of 2")
+	assert.Equal(t, fox+": FAILED\n"+empty+": MISSING\n", buf.String())
+
+	assert.Error(t, sum([]string{"sum", "-c", filepath.Join(dir, "missing")}, &buf))
+	assert.Error(t, sum([]string{"sum", "-c", fox}, &buf))
+	assert.Error(t, sum([]string{"sum", "-unknown"}, &buf))
+}
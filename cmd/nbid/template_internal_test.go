@@ -0,0 +1,46 @@
+// MIT License
+//
+// Copyright (c) 2021 Iván Szkiba
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_render(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	err := render(strings.NewReader(`id: {{nbid .}} short: {{nbidShort (nbid "The quick brown fox jumps over the lazy dog")}}`), &buf,
+		"The quick brown fox jumps over the lazy dog")
+
+	assert.Nil(t, err)
+	assert.Equal(t, "id: QUKFNCO7QU098QEAJAUB021E9S short: QUKFNCO7", buf.String())
+
+	assert.Error(t, render(strings.NewReader(`{{nbidParse "XXX"}}`), &buf, ""))
+	assert.Error(t, render(strings.NewReader(`{{nbidParse`), &buf, ""))
+}
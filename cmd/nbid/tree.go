@@ -0,0 +1,81 @@
+// MIT License
+//
+// Copyright (c) 2021 Iván Szkiba
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/szkiba/nbid/tree"
+)
+
+const treeUsage = `usage: nbid tree [-a] [-j workers] <dir>
+
+Print Merkle tree NBID of directory, or NBIDs of every entry with -a.
+
+`
+
+var errMissingDir = errors.New("missing directory argument")
+
+func treeCmd(args []string, stdout io.Writer) error {
+	flags := flag.NewFlagSet(args[0], flag.ContinueOnError)
+
+	flags.Usage = func() {
+		fmt.Fprint(flags.Output(), treeUsage)
+		flags.PrintDefaults()
+	}
+
+	all := flags.Bool("a", false, "print every entry as ID, type, permissions and path")
+	workers := flags.Int("j", 0, "number of parallel hashing workers (default number of CPUs)")
+
+	if err := flags.Parse(args[1:]); err != nil {
+		return err
+	}
+
+	if flags.NArg() != 1 {
+		flags.Usage()
+
+		return errMissingDir
+	}
+
+	t, err := tree.Walk(tree.DirFS(flags.Arg(0)), ".", tree.Options{Workers: *workers})
+	if err != nil {
+		return err
+	}
+
+	if !*all {
+		_, err = fmt.Fprintln(stdout, t.Root)
+
+		return err
+	}
+
+	for _, e := range t.Entries {
+		if _, err := fmt.Fprintf(stdout, "%s %-7s %04o %s\n", e.ID, e.Type, uint32(e.Perm), e.Path); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,63 @@
+// MIT License
+//
+// Copyright (c) 2021 Iván Szkiba
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/szkiba/nbid/tree"
+)
+
+func Test_treeCmd(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	assert.Nil(t, os.WriteFile(filepath.Join(dir, "a.txt"), []byte("hello"), 0o600))
+
+	want, err := tree.Sum(tree.DirFS(dir), ".")
+	assert.Nil(t, err)
+
+	var buf bytes.Buffer
+
+	assert.Nil(t, treeCmd([]string{"tree", dir}, &buf))
+	assert.Equal(t, want.String()+"\n", buf.String())
+
+	buf.Reset()
+
+	assert.Nil(t, treeCmd([]string{"tree", "-a", "-j", "1", dir}, &buf))
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+
+	assert.Len(t, lines, 2)
+	assert.True(t, strings.HasPrefix(lines[0], want.String()+" dir "))
+	assert.Equal(t, "5JP4REIVM2HGS9N87CLCBEF2JO file    0600 a.txt", lines[1])
+
+	assert.Error(t, treeCmd([]string{"tree"}, &buf))
+	assert.Error(t, treeCmd([]string{"tree", filepath.Join(dir, "missing")}, &buf))
+}
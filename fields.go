@@ -0,0 +1,186 @@
+// MIT License
+//
+// Copyright (c) 2021 Iván Szkiba
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package nbid
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+)
+
+// ErrUnsupportedField is returned when a value cannot be encoded as an ID field.
+var ErrUnsupportedField = errors.New("nbid: unsupported field type")
+
+// Field type tags of the field encoding.
+const (
+	tagString = 's'
+	tagBytes  = 'b'
+	tagInt    = 'i'
+	tagUint   = 'u'
+	tagBool   = 't'
+	tagTime   = 'T'
+	tagNBID   = 'n'
+)
+
+var (
+	typeTime = reflect.TypeOf(time.Time{})
+)
+
+// NewFields returns an NBID derived from a sequence of typed fields.
+//
+// Supported field types are strings, byte slices, signed and unsigned
+// integers of any size, booleans, time.Time and NBID (including ID[T] and other
+// types of package nbid with NBID as underlying type). Pointers and interfaces are
+// dereferenced; nil values and other types result in ErrUnsupportedField. Other 16
+// byte arrays (like UUIDs) are not NBIDs, convert them to NBID or []byte explicitly.
+//
+// Every field is encoded as a one byte type tag, the payload length as 8 byte
+// big endian unsigned integer, then the payload:
+//
+//	tag  type        payload
+//	's'  string      UTF-8 bytes as is
+//	'b'  []byte      bytes as is
+//	'i'  intN        8 byte big endian two's complement
+//	'u'  uintN       8 byte big endian
+//	't'  bool        1 byte, 0x00 or 0x01
+//	'T'  time.Time   8 byte big endian Unix seconds, 4 byte big endian nanoseconds
+//	'n'  NBID        16 bytes
+//
+// The ID is New of the concatenated field encodings. The encoding is injective,
+// so ("ab", "c") and ("a", "bc") or 1 and "1" never give the same input to
+// the hash function. Integers are widened before encoding, so int8(1) and int64(1)
+// are the same field, time zone and monotonic clock reading of times are ignored.
+// See FIELDS.md for the specification with test vectors.
+func NewFields(fields ...interface{}) (NBID, error) {
+	data, err := AppendFields(nil, fields...)
+	if err != nil {
+		return Nil, err
+	}
+
+	return New(data), nil
+}
+
+// AppendFields appends the encoding of fields (see NewFields) to b and returns the extended buffer.
+// The encoding of a field sequence is the concatenation of the encodings of its parts,
+// so long sequences can be hashed incrementally.
+func AppendFields(b []byte, fields ...interface{}) ([]byte, error) {
+	var err error
+
+	for i, field := range fields {
+		if b, err = appendField(b, reflect.ValueOf(field)); err != nil {
+			return nil, fmt.Errorf("%w: field %d", err, i)
+		}
+	}
+
+	return b, nil
+}
+
+// MustNewFields is like NewFields but panics if a field cannot be encoded.
+func MustNewFields(fields ...interface{}) NBID {
+	id, err := NewFields(fields...)
+	if err != nil {
+		panic(err)
+	}
+
+	return id
+}
+
+// appendField appends the encoding of v to b: a type tag byte, the length of
+// the payload as 8 byte big endian integer, then the payload.
+func appendField(b []byte, v reflect.Value) ([]byte, error) {
+	if !v.IsValid() {
+		return nil, fmt.Errorf("%w: nil", ErrUnsupportedField)
+	}
+
+	t := v.Type()
+
+	switch {
+	case t == typeTime:
+		tm := v.Interface().(time.Time) //nolint:forcetypeassert
+
+		var p [12]byte
+
+		binary.BigEndian.PutUint64(p[:8], uint64(tm.Unix()))
+		binary.BigEndian.PutUint32(p[8:], uint32(tm.Nanosecond()))
+
+		return appendTagged(b, tagTime, p[:]), nil
+
+	case t.Kind() == reflect.Array && t.PkgPath() == typeNBID.PkgPath() && t.ConvertibleTo(typeNBID):
+		id := v.Convert(typeNBID).Interface().(NBID) //nolint:forcetypeassert
+
+		return appendTagged(b, tagNBID, id[:]), nil
+
+	case t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8:
+		return appendTagged(b, tagBytes, v.Bytes()), nil
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return appendTagged(b, tagString, []byte(v.String())), nil
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		var p [8]byte
+
+		binary.BigEndian.PutUint64(p[:], uint64(v.Int()))
+
+		return appendTagged(b, tagInt, p[:]), nil
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		var p [8]byte
+
+		binary.BigEndian.PutUint64(p[:], v.Uint())
+
+		return appendTagged(b, tagUint, p[:]), nil
+
+	case reflect.Bool:
+		p := []byte{0}
+		if v.Bool() {
+			p[0] = 1
+		}
+
+		return appendTagged(b, tagBool, p), nil
+
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return nil, fmt.Errorf("%w: nil %s", ErrUnsupportedField, t)
+		}
+
+		return appendField(b, v.Elem())
+
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedField, t)
+	}
+}
+
+func appendTagged(b []byte, tag byte, payload []byte) []byte {
+	var l [8]byte
+
+	binary.BigEndian.PutUint64(l[:], uint64(len(payload)))
+
+	b = append(b, tag)
+	b = append(b, l[:]...)
+
+	return append(b, payload...)
+}
@@ -0,0 +1,161 @@
+// MIT License
+//
+// Copyright (c) 2021 Iván Szkiba
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package nbid_test
+
+import (
+	"encoding/hex"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/szkiba/nbid"
+)
+
+// Test vectors of FIELDS.md, computed with an independent implementation.
+var fieldsVectors = []struct {
+	fields []interface{}
+	data   string
+	id     string
+}{
+	{[]interface{}{}, "", "SEOC8GKOVGE196NRUJ49IRTP4G"},
+	{[]interface{}{"ab", "c"}, "730000000000000002616273000000000000000163", "2R7UFEJ183VO8CISEIP4OSPMM8"},
+	{[]interface{}{"a", "bc"}, "730000000000000001617300000000000000026263", "D2Q26VEO0V2Q3IV5K7VPMTA2JS"},
+	{[]interface{}{1}, "6900000000000000080000000000000001", "682AH5GFFU8GP5G98H6I9125IC"},
+	{[]interface{}{"1"}, "73000000000000000131", "74UP7M2NSFV09N7JGVOK7MKM9O"},
+	{[]interface{}{uint(1)}, "7500000000000000080000000000000001", "6DO452KOF77DRGPK2L498RDJ68"},
+	{[]interface{}{-1}, "690000000000000008ffffffffffffffff", "CDTDQ827QKVDC93K19BV28MPE0"},
+	{[]interface{}{true}, "74000000000000000101", "IIKU7N36K4110HG5HHLAHDQTAG"},
+	{[]interface{}{[]byte("1")}, "62000000000000000131", "TISTN2M9JNSP2LQ741U6417GBG"},
+	{
+		[]interface{}{time.Unix(1615723200, 500)},
+		"54000000000000000c00000000604dfac0000001f4",
+		"CUA30TB537VH6H0PLVC7L1AGR4",
+	},
+	{
+		[]interface{}{nbid.Nil},
+		"6e000000000000001000000000000000000000000000000000",
+		"HEGJ5ITVM9H7H8D9C4KOUBERMK",
+	},
+	{
+		[]interface{}{"acme", 42},
+		"73000000000000000461636d65690000000000000008000000000000002a",
+		"MOHH1RAJAOA6CNI6FIFIN9HHN0",
+	},
+}
+
+func TestNewFieldsVectors(t *testing.T) {
+	t.Parallel()
+
+	for _, v := range fieldsVectors {
+		data, err := hex.DecodeString(v.data)
+		assert.Nil(t, err)
+
+		id, err := nbid.NewFields(v.fields...)
+		assert.Nil(t, err)
+		assert.Equal(t, v.id, id.String(), "%v", v.fields)
+		assert.Equal(t, nbid.New(data), id, "%v", v.fields)
+	}
+}
+
+func TestAppendFields(t *testing.T) {
+	t.Parallel()
+
+	data, err := nbid.AppendFields(nil, "acme")
+	assert.Nil(t, err)
+
+	data, err = nbid.AppendFields(data, 42)
+	assert.Nil(t, err)
+
+	assert.Equal(t, nbid.MustNewFields("acme", 42), nbid.New(data))
+
+	_, err = nbid.AppendFields(data, 1.5)
+	assert.True(t, errors.Is(err, nbid.ErrUnsupportedField))
+}
+
+func TestNewFieldsInjective(t *testing.T) {
+	t.Parallel()
+
+	seen := map[nbid.NBID]int{}
+
+	for i, v := range fieldsVectors {
+		id := nbid.MustNewFields(v.fields...)
+
+		prev, ok := seen[id]
+		assert.False(t, ok, "%v collides with %v", v.fields, fieldsVectors[prev].fields)
+
+		seen[id] = i
+	}
+}
+
+func TestNewFieldsTypes(t *testing.T) {
+	t.Parallel()
+
+	type name string
+
+	type octet uint8
+
+	type blob []octet
+
+	one := 1
+	utc := time.Date(2021, 3, 14, 12, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, nbid.MustNewFields(1), nbid.MustNewFields(int8(1)))
+	assert.Equal(t, nbid.MustNewFields(1), nbid.MustNewFields(&one))
+	assert.Equal(t, nbid.MustNewFields("foo"), nbid.MustNewFields(name("foo")))
+	assert.Equal(t, nbid.MustNewFields([]byte{1, 2}), nbid.MustNewFields([]octet{1, 2}))
+	assert.Equal(t, nbid.MustNewFields([]byte{1, 2}), nbid.MustNewFields(blob{1, 2}))
+	assert.Equal(t, nbid.MustNewFields(utc), nbid.MustNewFields(utc.In(time.FixedZone("CET", 3600))))
+	assert.NotEqual(t, nbid.MustNewFields(uint8(1)), nbid.MustNewFields(int8(1)))
+	assert.NotEqual(t, nbid.MustNewFields(false), nbid.MustNewFields(0))
+
+	id := nbid.New([]byte("foo"))
+
+	assert.Equal(t, nbid.MustNewFields(id), nbid.MustNewFields(nbid.ID[user](id)))
+	assert.NotEqual(t, nbid.MustNewFields(id), nbid.MustNewFields(id[:]))
+}
+
+func TestNewFieldsErrors(t *testing.T) {
+	t.Parallel()
+
+	type uuid [16]byte
+
+	for _, v := range []interface{}{nil, 1.5, (*int)(nil), []string{"a"}, struct{}{}, [16]byte{}, uuid{}} {
+		_, err := nbid.NewFields("ok", v)
+		assert.True(t, errors.Is(err, nbid.ErrUnsupportedField), "%T", v)
+	}
+
+	assert.Panics(t, func() { nbid.MustNewFields(1.5) })
+}
+
+func TestFromStructFields(t *testing.T) {
+	t.Parallel()
+
+	id, err := nbid.FromStruct(orderKey{Tenant: "acme", Number: 42})
+	assert.Nil(t, err)
+
+	data, err := hex.DecodeString("73000000000000000461636d65690000000000000008000000000000002a")
+	assert.Nil(t, err)
+
+	assert.Equal(t, nbid.NewNamespaced(nbid.New([]byte("order")), data), id)
+}
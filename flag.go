@@ -0,0 +1,117 @@
+// MIT License
+//
+// Copyright (c) 2021 Iván Szkiba
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package nbid
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// ErrEnvNotSet is returned by FromEnv when the environment variable is not set or empty.
+var ErrEnvNotSet = errors.New("nbid: environment variable not set")
+
+// Set implements flag.Value, it parses s into NBID.
+func (id *NBID) Set(s string) error {
+	return id.UnmarshalText([]byte(s))
+}
+
+// Type implements the github.com/spf13/pflag Value interface.
+func (id *NBID) Type() string {
+	return "nbid"
+}
+
+// Flag defines an NBID flag with specified name and usage string in fs.
+// The default value is Nil. The return value is the address of an NBID
+// variable that stores the value of the flag.
+func Flag(fs *flag.FlagSet, name string, usage string) *NBID {
+	id := new(NBID)
+
+	fs.Var(id, name, usage)
+
+	return id
+}
+
+// SliceValue is a flag.Value collecting NBIDs of a repeated flag.
+// Each value may also contain comma separated NBIDs.
+type SliceValue []NBID
+
+// String implements flag.Value.
+func (s *SliceValue) String() string {
+	if s == nil {
+		return ""
+	}
+
+	items := make([]string, len(*s))
+	for i, id := range *s {
+		items[i] = id.String()
+	}
+
+	return strings.Join(items, ",")
+}
+
+// Set implements flag.Value, it appends the NBIDs parsed from value.
+func (s *SliceValue) Set(value string) error {
+	ids, err := parseList(value)
+	if err != nil {
+		return err
+	}
+
+	*s = append(*s, ids...)
+
+	return nil
+}
+
+// Type implements the github.com/spf13/pflag Value interface.
+func (s *SliceValue) Type() string {
+	return "nbidSlice"
+}
+
+// SliceFlag defines a repeatable NBID flag with specified name and usage string in fs.
+// The return value is the address of a slice that stores the values of the flag.
+func SliceFlag(fs *flag.FlagSet, name string, usage string) *[]NBID {
+	s := new(SliceValue)
+
+	fs.Var(s, name, usage)
+
+	return (*[]NBID)(s)
+}
+
+// FromEnv returns the NBID from the environment variable named by key.
+// Returns an error wrapping ErrEnvNotSet if the variable is not set or empty,
+// or wrapping a *ParseError if its value is not a valid NBID.
+func FromEnv(key string) (NBID, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return Nil, fmt.Errorf("%w: %s", ErrEnvNotSet, key)
+	}
+
+	id, err := Parse(value)
+	if err != nil {
+		return Nil, fmt.Errorf("nbid: environment variable %s: %w", key, err)
+	}
+
+	return id, nil
+}
@@ -0,0 +1,79 @@
+// MIT License
+//
+// Copyright (c) 2021 Iván Szkiba
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package nbid_test
+
+import (
+	"errors"
+	"flag"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/szkiba/nbid"
+)
+
+func TestFlag(t *testing.T) {
+	t.Parallel()
+
+	a := nbid.MustParse("QUKFNCO7QU098QEAJAUB021E9S")
+	b := nbid.MustParse("ABCDEFGHIJKLMNOPQRSTUV1234")
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+
+	tenant := nbid.Flag(fs, "tenant", "tenant ID")
+	ids := nbid.SliceFlag(fs, "id", "IDs")
+
+	err := fs.Parse([]string{"-tenant", a.String(), "-id", a.String(), "-id", b.String() + "," + a.String()})
+	assert.Nil(t, err)
+
+	assert.Equal(t, a, *tenant)
+	assert.Equal(t, []nbid.NBID{a, b, a}, *ids)
+	assert.Equal(t, a.String()+","+b.String()+","+a.String(), fs.Lookup("id").Value.String())
+
+	assert.Error(t, fs.Parse([]string{"-tenant", "XXX"}))
+	assert.Error(t, fs.Parse([]string{"-id", "XXX"}))
+
+	assert.Equal(t, "nbid", tenant.Type())
+	assert.Equal(t, "nbidSlice", (*nbid.SliceValue)(ids).Type())
+}
+
+func TestFromEnv(t *testing.T) {
+	t.Setenv("NBID_TEST_TENANT", "QUKFNCO7QU098QEAJAUB021E9S")
+	t.Setenv("NBID_TEST_INVALID", "XXX")
+
+	id, err := nbid.FromEnv("NBID_TEST_TENANT")
+	assert.Nil(t, err)
+	assert.Equal(t, nbid.MustParse("QUKFNCO7QU098QEAJAUB021E9S"), id)
+
+	_, err = nbid.FromEnv("NBID_TEST_MISSING")
+	assert.True(t, errors.Is(err, nbid.ErrEnvNotSet))
+	assert.Contains(t, err.Error(), "NBID_TEST_MISSING")
+
+	_, err = nbid.FromEnv("NBID_TEST_INVALID")
+
+	var perr *nbid.ParseError
+
+	assert.True(t, errors.As(err, &perr))
+	assert.Contains(t, err.Error(), "NBID_TEST_INVALID")
+}
@@ -0,0 +1,72 @@
+// MIT License
+//
+// Copyright (c) 2021 Iván Szkiba
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package nbid
+
+import (
+	"fmt"
+	"log/slog"
+)
+
+// LogValue implements slog.LogValuer, NBIDs are logged in string form.
+func (id NBID) LogValue() slog.Value {
+	return slog.StringValue(id.String())
+}
+
+// kind returns "nil" for the Nil NBID and "id" otherwise.
+// Name based and random NBIDs are indistinguishable, so there is no finer kind.
+func (id NBID) kind() string {
+	if id.IsNil() {
+		return "nil"
+	}
+
+	return "id"
+}
+
+// Format implements fmt.Formatter.
+//
+//	%v, %s  string form
+//	%q      double-quoted string form
+//	%x, %X  lower and upper case hex form of the binary representation
+//	%+v     verbose form: string, hex and kind
+//	%#v     Go syntax (nbid.MustParse call)
+//
+// Width, precision and flags are applied like for strings and byte slices.
+func (id NBID) Format(f fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		switch {
+		case f.Flag('#'):
+			fmt.Fprintf(f, "nbid.MustParse(%q)", id.String())
+		case f.Flag('+'):
+			fmt.Fprintf(f, "%s (hex: %x, kind: %s)", id.String(), id[:], id.kind())
+		default:
+			fmt.Fprintf(f, fmt.FormatString(f, 's'), id.String())
+		}
+	case 's', 'q':
+		fmt.Fprintf(f, fmt.FormatString(f, verb), id.String())
+	case 'x', 'X':
+		fmt.Fprintf(f, fmt.FormatString(f, verb), id[:])
+	default:
+		fmt.Fprintf(f, "%%!%c(nbid.NBID=%s)", verb, id.String())
+	}
+}
@@ -0,0 +1,96 @@
+// MIT License
+//
+// Copyright (c) 2021 Iván Szkiba
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package nbid_test
+
+import (
+	"bytes"
+	"fmt"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/szkiba/nbid"
+)
+
+func TestFormat(t *testing.T) {
+	t.Parallel()
+
+	id := nbid.MustParse("QUKFNCO7QU098QEAJAUB021E9S")
+
+	tests := []struct {
+		format string
+		id     nbid.NBID
+		out    string
+	}{
+		{format: "%v", id: id, out: "QUKFNCO7QU098QEAJAUB021E9S"},
+		{format: "%s", id: id, out: "QUKFNCO7QU098QEAJAUB021E9S"},
+		{format: "%.4s", id: id, out: "QUKF"},
+		{format: "%-28s|", id: id, out: "QUKFNCO7QU098QEAJAUB021E9S  |"},
+		{format: "%q", id: id, out: `"QUKFNCO7QU098QEAJAUB021E9S"`},
+		{format: "%x", id: id, out: "d7a8fbb307d7809469ca9abcb0082e4f"},
+		{format: "%X", id: id, out: "D7A8FBB307D7809469CA9ABCB0082E4F"},
+		{
+			format: "%+v",
+			id:     id,
+			out:    "QUKFNCO7QU098QEAJAUB021E9S (hex: d7a8fbb307d7809469ca9abcb0082e4f, kind: id)",
+		},
+		{
+			format: "%+v",
+			id:     nbid.Nil,
+			out:    "00000000000000000000000000 (hex: 00000000000000000000000000000000, kind: nil)",
+		},
+		{format: "%#v", id: id, out: `nbid.MustParse("QUKFNCO7QU098QEAJAUB021E9S")`},
+		{format: "%d", id: id, out: "%!d(nbid.NBID=QUKFNCO7QU098QEAJAUB021E9S)"},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.format, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.out, fmt.Sprintf(tt.format, tt.id))
+		})
+	}
+
+	// pointers are formatted the same way
+	assert.Equal(t, "QUKFNCO7QU098QEAJAUB021E9S", fmt.Sprintf("%v", &id))
+}
+
+func TestLogValue(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+
+			return a
+		},
+	}))
+
+	logger.Info("hello", "id", nbid.MustParse("QUKFNCO7QU098QEAJAUB021E9S"))
+
+	assert.Equal(t, "level=INFO msg=hello id=QUKFNCO7QU098QEAJAUB021E9S\n", buf.String())
+}
@@ -1,5 +1,15 @@
 module github.com/szkiba/nbid
 
-go 1.14
+go 1.22
 
-require github.com/stretchr/testify v1.7.0
+require (
+	github.com/gorilla/schema v1.4.1
+	github.com/stretchr/testify v1.7.0
+	golang.org/x/text v0.22.0
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.0 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	gopkg.in/yaml.v3 v3.0.0-20200313102051-9f266ea9e77c // indirect
+)
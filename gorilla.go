@@ -23,15 +23,192 @@
 package nbid
 
 import (
+	"errors"
+	"fmt"
 	"reflect"
+	"strings"
 )
 
-// Convert is a custom converter function for github.com/gorilla/schema decoder.
-func Convert(value string) reflect.Value {
+// ErrNoRegisterConverter is returned by RegisterConverters when the decoder has no
+// suitable RegisterConverter method.
+var ErrNoRegisterConverter = errors.New("nbid: decoder has no RegisterConverter(interface{}, func(string) reflect.Value) method")
+
+// ConversionError is returned when a form value cannot be converted to an NBID based type.
+// Err is usually a *ParseError describing why the value was rejected.
+type ConversionError struct {
+	Field string       // name of the form field, may be empty
+	Type  reflect.Type // the target type
+	Err   error        // the underlying error
+}
+
+func (e *ConversionError) Error() string {
+	if e.Field == "" {
+		return fmt.Sprintf("nbid: error converting value to %s: %v", e.Type, e.Err)
+	}
+
+	return fmt.Sprintf("nbid: error converting value for %q to %s: %v", e.Field, e.Type, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *ConversionError) Unwrap() error {
+	return e.Err
+}
+
+var (
+	typeNBID     = reflect.TypeOf(Nil)
+	typeNBIDPtr  = reflect.TypeOf(&Nil)
+	typeNBIDs    = reflect.TypeOf([]NBID{})
+	typeNullNBID = reflect.TypeOf(NullNBID{})
+)
+
+// ConvertField converts the value of the named form field to typ.
+// Supported types are NBID, *NBID, []NBID and NullNBID.
+// Values for []NBID may be comma separated, empty items are skipped.
+// An empty value results in a nil pointer, an empty slice or an invalid NullNBID.
+// Errors are returned as *ConversionError.
+func ConvertField(field string, typ reflect.Type, value string) (reflect.Value, error) {
+	var (
+		val interface{}
+		err error
+	)
+
+	switch typ {
+	case typeNBID:
+		val, err = Parse(value)
+
+	case typeNBIDPtr:
+		val, err = parsePtr(value)
+
+	case typeNBIDs:
+		val, err = parseList(value)
+
+	case typeNullNBID:
+		n := NullNBID{}
+		err = n.UnmarshalText([]byte(value))
+		val = n
+
+	default:
+		err = fmt.Errorf("%w: unsupported type %v", ErrInvalidID, typ)
+	}
+
+	if err != nil {
+		return invalidValue, &ConversionError{Field: field, Type: typ, Err: err}
+	}
+
+	return reflect.ValueOf(val), nil
+}
+
+func parsePtr(value string) (*NBID, error) {
+	if value == "" {
+		return nil, nil
+	}
+
 	id, err := Parse(value)
+	if err != nil {
+		return nil, err
+	}
+
+	return &id, nil
+}
+
+func parseList(value string) ([]NBID, error) {
+	ids := []NBID{}
+
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		id, err := Parse(item)
+		if err != nil {
+			return nil, err
+		}
+
+		ids = append(ids, id)
+	}
+
+	return ids, nil
+}
+
+func convert(typ reflect.Type, value string) reflect.Value {
+	val, err := ConvertField("", typ, value)
 	if err != nil {
 		return invalidValue
 	}
 
-	return reflect.ValueOf(id)
+	return val
+}
+
+// Convert is a custom converter function for github.com/gorilla/schema decoder.
+func Convert(value string) reflect.Value {
+	return convert(typeNBID, value)
+}
+
+// ConvertPtr is a custom *NBID converter function for github.com/gorilla/schema decoder.
+func ConvertPtr(value string) reflect.Value {
+	return convert(typeNBIDPtr, value)
+}
+
+// ConvertSlice is a custom []NBID converter function for github.com/gorilla/schema decoder.
+// The value may contain comma separated NBIDs.
+func ConvertSlice(value string) reflect.Value {
+	return convert(typeNBIDs, value)
+}
+
+// ConvertNull is a custom NullNBID converter function for github.com/gorilla/schema decoder.
+func ConvertNull(value string) reflect.Value {
+	return convert(typeNullNBID, value)
+}
+
+// RegisterConverters prepares a github.com/gorilla/schema decoder (or any other decoder
+// with a compatible RegisterConverter method) for decoding NBID based fields without
+// importing it. It returns ErrNoRegisterConverter if decoder is not compatible.
+//
+// Converters are registered for NBID and *NBID. gorilla/schema needs a converter for the
+// element type to decode []NBID fields from repeated keys; the elements themselves are
+// decoded with UnmarshalText, so conversion errors carry the index and the *ParseError
+// reason. For the same reason gorilla/schema doesn't split comma separated values of
+// []NBID fields, use repeated keys instead.
+//
+// An empty value results in Nil. gorilla/schema allocates pointer fields before
+// converting, so an empty *NBID field results in a pointer to Nil, not a nil pointer;
+// use NullNBID to tell an empty value apart. NullNBID is left to UnmarshalText, which
+// reports errors with details.
+//
+// Single NBID fields are decoded with the registered converter, which gorilla/schema
+// prefers over UnmarshalText and reports without details. Use ConvertField to get a
+// *ConversionError describing the reason.
+func RegisterConverters(decoder interface{}) error {
+	method := reflect.ValueOf(decoder).MethodByName("RegisterConverter")
+	if !method.IsValid() {
+		return ErrNoRegisterConverter
+	}
+
+	mtype := method.Type()
+	if mtype.NumIn() != 2 || mtype.In(0).Kind() != reflect.Interface ||
+		!reflect.TypeOf(Convert).ConvertibleTo(mtype.In(1)) {
+		return ErrNoRegisterConverter
+	}
+
+	register := func(value interface{}, fn func(string) reflect.Value) {
+		method.Call([]reflect.Value{
+			reflect.ValueOf(&value).Elem(),
+			reflect.ValueOf(fn).Convert(mtype.In(1)),
+		})
+	}
+
+	register(Nil, convertForm)
+	register(&Nil, ConvertPtr)
+
+	return nil
+}
+
+// convertForm is like Convert, but it converts an empty value to Nil.
+func convertForm(value string) reflect.Value {
+	if value == "" {
+		return reflect.ValueOf(Nil)
+	}
+
+	return Convert(value)
 }
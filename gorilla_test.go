@@ -23,9 +23,13 @@
 package nbid_test
 
 import (
+	"errors"
+	"fmt"
+	"net/url"
 	"reflect"
 	"testing"
 
+	gorillaschema "github.com/gorilla/schema"
 	"github.com/stretchr/testify/assert"
 	"github.com/szkiba/nbid"
 )
@@ -43,3 +47,175 @@ func TestConvert(t *testing.T) {
 	val = nbid.Convert("XXX")
 	assert.Equal(t, reflect.Value{}, val)
 }
+
+func TestConvertPtr(t *testing.T) {
+	t.Parallel()
+
+	id := nbid.MustParse("QUKFNCO7QU098QEAJAUB021E9S")
+
+	val := nbid.ConvertPtr(id.String())
+	assert.Equal(t, &id, val.Interface())
+
+	val = nbid.ConvertPtr("")
+	assert.Nil(t, val.Interface())
+
+	val = nbid.ConvertPtr("XXX")
+	assert.Equal(t, reflect.Value{}, val)
+}
+
+func TestConvertSlice(t *testing.T) {
+	t.Parallel()
+
+	a := nbid.MustParse("QUKFNCO7QU098QEAJAUB021E9S")
+	b := nbid.MustParse("ABCDEFGHIJKLMNOPQRSTUV1234")
+
+	val := nbid.ConvertSlice(a.String() + ", " + b.String() + ",")
+	assert.Equal(t, []nbid.NBID{a, b}, val.Interface())
+
+	val = nbid.ConvertSlice("")
+	assert.Equal(t, []nbid.NBID{}, val.Interface())
+
+	val = nbid.ConvertSlice(a.String() + ",XXX")
+	assert.Equal(t, reflect.Value{}, val)
+}
+
+func TestConvertNull(t *testing.T) {
+	t.Parallel()
+
+	id := nbid.MustParse("QUKFNCO7QU098QEAJAUB021E9S")
+
+	val := nbid.ConvertNull(id.String())
+	assert.Equal(t, nbid.NullNBID{NBID: id, Valid: true}, val.Interface())
+
+	val = nbid.ConvertNull("")
+	assert.Equal(t, nbid.NullNBID{}, val.Interface())
+
+	val = nbid.ConvertNull("XXX")
+	assert.Equal(t, reflect.Value{}, val)
+}
+
+func TestConvertField(t *testing.T) {
+	t.Parallel()
+
+	_, err := nbid.ConvertField("owner", reflect.TypeOf(nbid.Nil), "XXX")
+
+	var cerr *nbid.ConversionError
+
+	assert.True(t, errors.As(err, &cerr))
+	assert.Equal(t, "owner", cerr.Field)
+	assert.Contains(t, err.Error(), `"owner"`)
+
+	var perr *nbid.ParseError
+
+	assert.True(t, errors.As(err, &perr))
+	assert.Equal(t, "XXX", perr.Input)
+	assert.True(t, errors.Is(err, nbid.ErrInvalidID))
+
+	_, err = nbid.ConvertField("count", reflect.TypeOf(0), "1")
+	assert.Error(t, err)
+}
+
+// converter mimics github.com/gorilla/schema Converter type.
+type converter func(string) reflect.Value
+
+type decoder struct {
+	converters map[reflect.Type]converter
+}
+
+func (d *decoder) RegisterConverter(value interface{}, fn converter) {
+	d.converters[reflect.TypeOf(value)] = fn
+}
+
+func TestRegisterConverters(t *testing.T) {
+	t.Parallel()
+
+	d := &decoder{converters: map[reflect.Type]converter{}}
+
+	assert.Nil(t, nbid.RegisterConverters(d))
+	assert.Len(t, d.converters, 2)
+
+	id := nbid.MustParse("QUKFNCO7QU098QEAJAUB021E9S")
+
+	conv := d.converters[reflect.TypeOf(nbid.Nil)]
+	assert.Equal(t, id, conv(id.String()).Interface())
+	assert.Equal(t, nbid.Nil, conv("").Interface())
+	assert.Equal(t, reflect.Value{}, conv("XXX"))
+
+	conv = d.converters[reflect.TypeOf(&nbid.Nil)]
+	assert.Equal(t, &id, conv(id.String()).Interface())
+	assert.Nil(t, conv("").Interface())
+
+	assert.ErrorIs(t, nbid.RegisterConverters(struct{}{}), nbid.ErrNoRegisterConverter)
+}
+
+func TestGorillaSchema(t *testing.T) {
+	t.Parallel()
+
+	type form struct {
+		Owner  nbid.NBID     `schema:"owner"`
+		Parent *nbid.NBID    `schema:"parent"`
+		Ref    nbid.NullNBID `schema:"ref"`
+	}
+
+	d := gorillaschema.NewDecoder()
+
+	assert.Nil(t, nbid.RegisterConverters(d))
+
+	a := nbid.MustParse("QUKFNCO7QU098QEAJAUB021E9S")
+	b := nbid.MustParse("ABCDEFGHIJKLMNOPQRSTUV1234")
+
+	var f form
+
+	err := d.Decode(&f, url.Values{"owner": {a.String()}, "parent": {b.String()}, "ref": {a.String()}})
+
+	assert.Nil(t, err)
+	assert.Equal(t, form{Owner: a, Parent: &b, Ref: nbid.NullNBID{NBID: a, Valid: true}}, f)
+
+	f = form{}
+
+	assert.Nil(t, d.Decode(&f, url.Values{"owner": {""}, "parent": {""}, "ref": {""}}))
+	assert.Equal(t, form{Parent: &nbid.Nil}, f, "gorilla/schema allocates pointer fields")
+
+	for _, key := range []string{"owner", "parent", "ref"} {
+		err := d.Decode(&form{}, url.Values{key: {"XXX"}})
+
+		assert.Error(t, err, key)
+		assert.Contains(t, err.Error(), fmt.Sprintf("%q", key))
+	}
+
+	err = d.Decode(&form{}, url.Values{"ref": {"XXX"}})
+	assert.Contains(t, err.Error(), "invalid length 3, expected 26")
+}
+
+func TestGorillaSchemaSlice(t *testing.T) {
+	t.Parallel()
+
+	type form struct {
+		Tags []nbid.NBID `schema:"tag"`
+	}
+
+	d := gorillaschema.NewDecoder()
+
+	assert.Nil(t, nbid.RegisterConverters(d))
+
+	a := nbid.MustParse("QUKFNCO7QU098QEAJAUB021E9S")
+	b := nbid.MustParse("ABCDEFGHIJKLMNOPQRSTUV1234")
+
+	var f form
+
+	assert.Nil(t, d.Decode(&f, url.Values{"tag": {a.String(), "", b.String()}}))
+	assert.Equal(t, []nbid.NBID{a, b}, f.Tags)
+
+	err := d.Decode(&form{}, url.Values{"tag": {a.String(), "XXX"}})
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), `index 1 of "tag"`)
+	assert.Contains(t, err.Error(), "invalid length 3, expected 26")
+
+	// gorilla/schema doesn't split comma separated values of text unmarshalers
+	err = d.Decode(&form{}, url.Values{"tag": {a.String() + "," + b.String()}})
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), `index 0 of "tag"`)
+	assert.Contains(t, err.Error(), "invalid length 53, expected 26")
+}
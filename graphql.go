@@ -0,0 +1,78 @@
+// MIT License
+//
+// Copyright (c) 2021 Iván Szkiba
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package nbid
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// MarshalGQL implements the github.com/99designs/gqlgen graphql.Marshaler interface.
+// NBID is written as a GraphQL string.
+func (id NBID) MarshalGQL(w io.Writer) {
+	io.WriteString(w, strconv.Quote(id.String())) //nolint:errcheck
+}
+
+// UnmarshalGQL implements the github.com/99designs/gqlgen graphql.Unmarshaler interface.
+// Only string input is accepted, a *ParseError is returned otherwise.
+func (id *NBID) UnmarshalGQL(v interface{}) error {
+	s, ok := v.(string)
+	if !ok {
+		return &ParseError{Input: fmt.Sprint(v), Reason: fmt.Sprintf("unsupported type %T, expected string", v)}
+	}
+
+	return id.UnmarshalText([]byte(s))
+}
+
+// MarshalGQL implements the github.com/99designs/gqlgen graphql.Marshaler interface.
+// An invalid NullNBID is written as null.
+func (n NullNBID) MarshalGQL(w io.Writer) {
+	if !n.Valid {
+		io.WriteString(w, "null") //nolint:errcheck
+
+		return
+	}
+
+	n.NBID.MarshalGQL(w)
+}
+
+// UnmarshalGQL implements the github.com/99designs/gqlgen graphql.Unmarshaler interface.
+// A nil input results in an invalid NullNBID.
+func (n *NullNBID) UnmarshalGQL(v interface{}) error {
+	if v == nil {
+		n.NBID, n.Valid = Nil, false
+
+		return nil
+	}
+
+	if err := n.NBID.UnmarshalGQL(v); err != nil {
+		n.Valid = false
+
+		return err
+	}
+
+	n.Valid = true
+
+	return nil
+}
@@ -0,0 +1,109 @@
+// MIT License
+//
+// Copyright (c) 2021 Iván Szkiba
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package nbid_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/szkiba/nbid"
+)
+
+// schema is an example GraphQL schema declaring NBID as a custom scalar.
+// With gqlgen, the scalar is bound to the Go type in gqlgen.yml:
+//
+//	models:
+//	  NBID:
+//	    model: github.com/szkiba/nbid.NBID
+const schema = `
+scalar NBID
+
+type User {
+  id: NBID!
+  manager: NBID
+}
+`
+
+func TestGQL(t *testing.T) {
+	t.Parallel()
+
+	assert.Contains(t, schema, "scalar NBID")
+
+	id := nbid.MustParse("QUKFNCO7QU098QEAJAUB021E9S")
+
+	var buf bytes.Buffer
+
+	id.MarshalGQL(&buf)
+	assert.Equal(t, `"QUKFNCO7QU098QEAJAUB021E9S"`, buf.String())
+
+	var got nbid.NBID
+
+	assert.Nil(t, got.UnmarshalGQL("QUKFNCO7QU098QEAJAUB021E9S"))
+	assert.Equal(t, id, got)
+
+	var perr *nbid.ParseError
+
+	err := got.UnmarshalGQL("XXX")
+	assert.True(t, errors.As(err, &perr))
+
+	err = got.UnmarshalGQL(42)
+	assert.True(t, errors.As(err, &perr))
+	assert.Equal(t, "unsupported type int, expected string", perr.Reason)
+}
+
+func TestNullGQL(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	nbid.NullNBID{}.MarshalGQL(&buf)
+	assert.Equal(t, "null", buf.String())
+
+	var n nbid.NullNBID
+
+	assert.Nil(t, n.UnmarshalGQL("QUKFNCO7QU098QEAJAUB021E9S"))
+	assert.True(t, n.Valid)
+
+	buf.Reset()
+	n.MarshalGQL(&buf)
+	assert.Equal(t, `"QUKFNCO7QU098QEAJAUB021E9S"`, buf.String())
+
+	assert.Nil(t, n.UnmarshalGQL(nil))
+	assert.False(t, n.Valid)
+
+	assert.Error(t, n.UnmarshalGQL(true))
+	assert.False(t, n.Valid)
+}
+
+func ExampleNBID_MarshalGQL() {
+	id := nbid.New([]byte("The quick brown fox jumps over the lazy dog"))
+
+	id.MarshalGQL(os.Stdout)
+	fmt.Println()
+
+	// Output: "QUKFNCO7QU098QEAJAUB021E9S"
+}
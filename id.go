@@ -28,7 +28,9 @@ import (
 	"crypto/sha256"
 	"encoding/base32"
 	"errors"
+	"fmt"
 	"hash"
+	"io"
 )
 
 var (
@@ -44,6 +46,22 @@ const (
 	rawLen     = 16 // binary raw len
 )
 
+// ParseError is returned when a string cannot be decoded into an NBID.
+// It wraps ErrInvalidID, so errors.Is(err, ErrInvalidID) still holds.
+type ParseError struct {
+	Input  string // the rejected input
+	Reason string // why the input was rejected
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("%s %q: %s", ErrInvalidID, e.Input, e.Reason)
+}
+
+// Unwrap returns ErrInvalidID.
+func (e *ParseError) Unwrap() error {
+	return ErrInvalidID
+}
+
 // NBID represents a Name Based ID.
 // The binary representation of the NBID is a 16 byte byte array.
 // The string representation is using base32 hex (w/o padding).
@@ -73,6 +91,34 @@ func New(data []byte) NBID {
 	return NewHash(sha256.New(), data)
 }
 
+// NewReader returns a new NBID derived from the SHA256 hash of the data read from r until EOF.
+// The data is hashed by streaming, so it is the same as New of the whole content
+// without reading it into memory.
+func NewReader(r io.Reader) (NBID, error) {
+	h := sha256.New()
+
+	if _, err := io.Copy(h, r); err != nil {
+		return Nil, err
+	}
+
+	var id NBID
+
+	copy(id[:], h.Sum(nil))
+
+	return id, nil
+}
+
+// NewNamespaced returns a new NBID derived from the SHA256 hash of the namespace
+// ns followed by data. Namespaces allow the same name to result different NBIDs
+// in different contexts. It is the same as calling:
+//
+//  New(append(ns.Bytes(), data...))
+func NewNamespaced(ns NBID, data []byte) NBID {
+	b := make([]byte, 0, rawLen+len(data))
+
+	return New(append(append(b, ns[:]...), data...))
+}
+
 // Random returns a new random generated NBID.
 // The strength of the IDs is based on the strength of the crypto/rand
 // package.
@@ -86,7 +132,8 @@ func Random() NBID {
 
 // Parse decodes s into an NBID or returns an error.
 // The string representation is using base32 hex (w/o padding).
-// Returns an error if the s does not have a length of 16.
+// Returns a *ParseError if the s does not have a length of 26 or contains
+// characters outside of the base32 hex alphabet.
 func Parse(s string) (NBID, error) {
 	i := &NBID{}
 	err := i.UnmarshalText([]byte(s))
@@ -133,16 +180,32 @@ func (id NBID) MarshalJSON() ([]byte, error) {
 }
 
 // UnmarshalText implements encoding/text TextUnmarshaler interface.
+// A *ParseError is returned if text is not a valid NBID.
 func (id *NBID) UnmarshalText(text []byte) error {
 	if len(text) != encodedLen {
-		return ErrInvalidID
+		return &ParseError{
+			Input:  string(text),
+			Reason: fmt.Sprintf("invalid length %d, expected %d", len(text), encodedLen),
+		}
 	}
 
-	_, err := base32.HexEncoding.WithPadding(base32.NoPadding).Decode(id[:], text)
+	var tmp NBID
+
+	_, err := base32.HexEncoding.WithPadding(base32.NoPadding).Decode(tmp[:], text)
 	if err != nil {
-		return ErrInvalidID
+		var cie base32.CorruptInputError
+		if errors.As(err, &cie) && int(cie) < len(text) {
+			return &ParseError{
+				Input:  string(text),
+				Reason: fmt.Sprintf("illegal character %q at offset %d", text[cie], int(cie)),
+			}
+		}
+
+		return &ParseError{Input: string(text), Reason: err.Error()}
 	}
 
+	*id = tmp
+
 	return nil
 }
 
@@ -23,9 +23,13 @@
 package nbid_test
 
 import (
+	"bytes"
 	"crypto/sha256"
 	"encoding/hex"
+	"errors"
+	"io"
 	"testing"
+	"testing/iotest"
 
 	"github.com/szkiba/nbid"
 
@@ -81,6 +85,20 @@ func TestNew(t *testing.T) {
 	assert.Equal(t, "QUKFNCO7QU098QEAJAUB021E9S", id.String())
 }
 
+func TestNewReader(t *testing.T) {
+	t.Parallel()
+
+	data := []byte("The quick brown fox jumps over the lazy dog")
+
+	id, err := nbid.NewReader(bytes.NewReader(data))
+
+	assert.Nil(t, err)
+	assert.Equal(t, nbid.New(data), id)
+
+	_, err = nbid.NewReader(iotest.ErrReader(io.ErrUnexpectedEOF))
+	assert.True(t, errors.Is(err, io.ErrUnexpectedEOF))
+}
+
 func TestRandom(t *testing.T) {
 	t.Parallel()
 
@@ -205,3 +223,47 @@ func TestBinary(t *testing.T) {
 		})
 	}
 }
+
+func TestParseError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		in     string
+		reason string
+	}{
+		{name: "length", in: "small", reason: "invalid length 5, expected 26"},
+		{name: "character", in: "ABCDEFGHIJKLMNOPQRSTUVWXYZ", reason: `illegal character 'W' at offset 22`},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := nbid.Parse(tt.in)
+
+			var perr *nbid.ParseError
+
+			assert.True(t, errors.As(err, &perr))
+			assert.True(t, errors.Is(err, nbid.ErrInvalidID))
+			assert.Equal(t, tt.in, perr.Input)
+			assert.Equal(t, tt.reason, perr.Reason)
+		})
+	}
+}
+
+func TestNewNamespaced(t *testing.T) {
+	t.Parallel()
+
+	data := []byte("The quick brown fox jumps over the lazy dog")
+	ns1 := nbid.New([]byte("ns1"))
+	ns2 := nbid.New([]byte("ns2"))
+
+	id1 := nbid.NewNamespaced(ns1, data)
+	id2 := nbid.NewNamespaced(ns2, data)
+
+	assert.NotEqual(t, id1, id2)
+	assert.NotEqual(t, nbid.New(data), id1)
+	assert.Equal(t, nbid.New(append(ns1.Bytes(), data...)), id1)
+	assert.Equal(t, id1, nbid.NewNamespaced(ns1, data))
+}
@@ -0,0 +1,280 @@
+// MIT License
+//
+// Copyright (c) 2021 Iván Szkiba
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package nbid
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+)
+
+// ErrInvalidJSON is returned when a JSON document cannot be canonicalized.
+var ErrInvalidJSON = errors.New("nbid: invalid JSON")
+
+// NewJSON returns a new NBID derived from the SHA256 hash of the RFC 8785 JSON
+// Canonicalization Scheme (JCS) form of raw, so documents differing only in key
+// order, whitespace, string escaping or number formatting result in the same NBID.
+// Duplicate object keys and numbers out of the IEEE 754 double precision range
+// are rejected.
+func NewJSON(raw []byte) (NBID, error) {
+	c, err := CanonicalJSON(raw)
+	if err != nil {
+		return Nil, err
+	}
+
+	return New(c), nil
+}
+
+// NewValue returns a new NBID derived from the canonical JSON form of v.
+// The value is marshalled with encoding/json, then canonicalized as by NewJSON.
+func NewValue(v interface{}) (NBID, error) {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return Nil, fmt.Errorf("%w: %v", ErrInvalidJSON, err)
+	}
+
+	return NewJSON(raw)
+}
+
+// CanonicalJSON returns the RFC 8785 JSON Canonicalization Scheme form of raw.
+func CanonicalJSON(raw []byte) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+
+	var buf bytes.Buffer
+
+	if err := canonicalValue(dec, &buf, ""); err != nil {
+		return nil, err
+	}
+
+	if _, err := dec.Token(); !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("%w: unexpected data after top-level value", ErrInvalidJSON)
+	}
+
+	return buf.Bytes(), nil
+}
+
+func canonicalValue(dec *json.Decoder, buf *bytes.Buffer, path string) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidJSON, err)
+	}
+
+	switch tok := tok.(type) {
+	case json.Delim:
+		if tok == '{' {
+			return canonicalObject(dec, buf, path)
+		}
+
+		return canonicalArray(dec, buf, path)
+
+	case string:
+		writeJCSString(buf, tok)
+
+	case json.Number:
+		s, err := formatJCSNumber(tok)
+		if err != nil {
+			return fmt.Errorf("%w: at %s: %v", ErrInvalidJSON, pathOrRoot(path), err)
+		}
+
+		buf.WriteString(s)
+
+	case bool:
+		buf.WriteString(strconv.FormatBool(tok))
+
+	case nil:
+		buf.WriteString("null")
+	}
+
+	return nil
+}
+
+func canonicalArray(dec *json.Decoder, buf *bytes.Buffer, path string) error {
+	buf.WriteByte('[')
+
+	for i := 0; dec.More(); i++ {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+
+		if err := canonicalValue(dec, buf, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+			return err
+		}
+	}
+
+	if _, err := dec.Token(); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidJSON, err)
+	}
+
+	buf.WriteByte(']')
+
+	return nil
+}
+
+type jcsMember struct {
+	key   string
+	utf16 []uint16
+	value []byte
+}
+
+func canonicalObject(dec *json.Decoder, buf *bytes.Buffer, path string) error {
+	var members []jcsMember
+
+	seen := make(map[string]struct{})
+
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidJSON, err)
+		}
+
+		key, _ := tok.(string)
+
+		if _, ok := seen[key]; ok {
+			return fmt.Errorf("%w: duplicate key %q in %s", ErrInvalidJSON, key, pathOrRoot(path))
+		}
+
+		seen[key] = struct{}{}
+
+		var value bytes.Buffer
+
+		if err := canonicalValue(dec, &value, path+"."+key); err != nil {
+			return err
+		}
+
+		members = append(members, jcsMember{key: key, utf16: utf16.Encode([]rune(key)), value: value.Bytes()})
+	}
+
+	if _, err := dec.Token(); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidJSON, err)
+	}
+
+	// RFC 8785 3.2.3: members are sorted by the UTF-16 code units of their keys
+	sort.Slice(members, func(i, j int) bool {
+		a, b := members[i].utf16, members[j].utf16
+		for k := 0; k < len(a) && k < len(b); k++ {
+			if a[k] != b[k] {
+				return a[k] < b[k]
+			}
+		}
+
+		return len(a) < len(b)
+	})
+
+	buf.WriteByte('{')
+
+	for i, m := range members {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+
+		writeJCSString(buf, m.key)
+		buf.WriteByte(':')
+		buf.Write(m.value)
+	}
+
+	buf.WriteByte('}')
+
+	return nil
+}
+
+func pathOrRoot(path string) string {
+	if path == "" {
+		return "$"
+	}
+
+	return "$" + path
+}
+
+// formatJCSNumber formats n as ECMAScript Number.prototype.toString does (RFC 8785 3.2.2.3).
+func formatJCSNumber(n json.Number) (string, error) {
+	f, err := strconv.ParseFloat(string(n), 64)
+	if err != nil || math.IsInf(f, 0) || math.IsNaN(f) {
+		return "", fmt.Errorf("non-finite number %s", n)
+	}
+
+	if f == 0 {
+		return "0", nil
+	}
+
+	sign := ""
+	if f < 0 {
+		sign, f = "-", -f
+	}
+
+	format := byte('e')
+	if f >= 1e-6 && f < 1e21 {
+		format = 'f'
+	}
+
+	s := strconv.FormatFloat(f, format, -1, 64)
+
+	// Go writes exponents with at least two digits ("1e+09"), ECMAScript does not
+	if e := strings.IndexByte(s, 'e'); e > 0 && s[e+2] == '0' {
+		s = s[:e+2] + s[e+3:]
+	}
+
+	return sign + s, nil
+}
+
+// writeJCSString writes s as a JSON string escaping only what RFC 8785 3.2.2.2 requires.
+func writeJCSString(buf *bytes.Buffer, s string) {
+	const hex = "0123456789abcdef"
+
+	buf.WriteByte('"')
+
+	for _, r := range s {
+		switch r {
+		case '"', '\\':
+			buf.WriteByte('\\')
+			buf.WriteRune(r)
+		case '\b':
+			buf.WriteString(`\b`)
+		case '\f':
+			buf.WriteString(`\f`)
+		case '\n':
+			buf.WriteString(`\n`)
+		case '\r':
+			buf.WriteString(`\r`)
+		case '\t':
+			buf.WriteString(`\t`)
+		default:
+			if r < 0x20 {
+				buf.WriteString(`\u00`)
+				buf.WriteByte(hex[r>>4])
+				buf.WriteByte(hex[r&0xf])
+			} else {
+				buf.WriteRune(r)
+			}
+		}
+	}
+
+	buf.WriteByte('"')
+}
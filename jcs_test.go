@@ -0,0 +1,193 @@
+// MIT License
+//
+// Copyright (c) 2021 Iván Szkiba
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package nbid_test
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"math"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/szkiba/nbid"
+)
+
+func TestCanonicalJSON(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		in   string
+		out  string
+	}{
+		{
+			// RFC 8785 3.2.2
+			name: "rfc_sample",
+			in: `{
+  "numbers": [333333333.33333329, 1E30, 4.50, 2e-3, 0.000000000000000000000000001],
+  "string": "\u20ac$\u000F\u000aA'\u0042\u0022\u005c\\\"\/",
+  "literals": [null, true, false]
+}`,
+			out: `{"literals":[null,true,false],"numbers":[333333333.3333333,1e+30,4.5,0.002,1e-27],` +
+				`"string":"€$\u000f\nA'B\"\\\\\"/"}`,
+		},
+		{
+			// RFC 8785 3.2.3
+			name: "rfc_sorting",
+			in: `{
+  "\u20ac": "Euro Sign",
+  "\r": "Carriage Return",
+  "\ufb33": "Hebrew Letter Dalet With Dagesh",
+  "1": "One",
+  "\ud83d\ude00": "Emoji: Grinning Face",
+  "\u0080": "Control",
+  "\u00f6": "Latin Small Letter O With Diaeresis"
+}`,
+			out: "{\"\\r\":\"Carriage Return\",\"1\":\"One\",\"\u0080\":\"Control\"," +
+				"\"\u00f6\":\"Latin Small Letter O With Diaeresis\",\"\u20ac\":\"Euro Sign\"," +
+				"\"\U0001F600\":\"Emoji: Grinning Face\",\"\ufb33\":\"Hebrew Letter Dalet With Dagesh\"}",
+		},
+		{name: "nested", in: ` { "b" : [ { "d":1, "c":2 } ], "a" : {} } `, out: `{"a":{},"b":[{"c":2,"d":1}]}`},
+		{name: "scalar", in: ` "<&>\u2028" `, out: "\"<&>\u2028\""},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := nbid.CanonicalJSON([]byte(tt.in))
+			assert.Nil(t, err)
+			assert.Equal(t, tt.out, string(got))
+
+			id, err := nbid.NewJSON([]byte(tt.in))
+			assert.Nil(t, err)
+			assert.Equal(t, nbid.New([]byte(tt.out)), id)
+		})
+	}
+}
+
+func TestCanonicalJSONNumbers(t *testing.T) {
+	t.Parallel()
+
+	// RFC 8785 Appendix B
+	tests := []struct {
+		bits string
+		out  string
+	}{
+		{bits: "0000000000000000", out: "0"},
+		{bits: "8000000000000000", out: "0"},
+		{bits: "0000000000000001", out: "5e-324"},
+		{bits: "8000000000000001", out: "-5e-324"},
+		{bits: "7fefffffffffffff", out: "1.7976931348623157e+308"},
+		{bits: "ffefffffffffffff", out: "-1.7976931348623157e+308"},
+		{bits: "4340000000000000", out: "9007199254740992"},
+		{bits: "c340000000000000", out: "-9007199254740992"},
+		{bits: "4430000000000000", out: "295147905179352830000"},
+		{bits: "44b52d02c7e14af5", out: "9.999999999999997e+22"},
+		{bits: "44b52d02c7e14af6", out: "1e+23"},
+		{bits: "44b52d02c7e14af7", out: "1.0000000000000001e+23"},
+		{bits: "444b1ae4d6e2ef4e", out: "999999999999999700000"},
+		{bits: "444b1ae4d6e2ef4f", out: "999999999999999900000"},
+		{bits: "444b1ae4d6e2ef50", out: "1e+21"},
+		{bits: "3eb0c6f7a0b5ed8c", out: "9.999999999999997e-7"},
+		{bits: "3eb0c6f7a0b5ed8d", out: "0.000001"},
+		{bits: "41b3de4355555553", out: "333333333.3333332"},
+		{bits: "41b3de4355555554", out: "333333333.33333325"},
+		{bits: "41b3de4355555555", out: "333333333.3333333"},
+		{bits: "41b3de4355555556", out: "333333333.3333334"},
+		{bits: "41b3de4355555557", out: "333333333.33333343"},
+		{bits: "becbf647612f3696", out: "-0.0000033333333333333333"},
+		{bits: "43143ff3c1cb0959", out: "1424953923781206.2"},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.bits, func(t *testing.T) {
+			t.Parallel()
+
+			b, err := hex.DecodeString(tt.bits)
+			assert.Nil(t, err)
+
+			f := math.Float64frombits(binary.BigEndian.Uint64(b))
+
+			// the shortest round-trip form is parsed back to the same value
+			got, err := nbid.CanonicalJSON([]byte(strconv.FormatFloat(f, 'g', -1, 64)))
+			assert.Nil(t, err)
+			assert.Equal(t, tt.out, string(got))
+		})
+	}
+}
+
+func TestCanonicalJSONErrors(t *testing.T) {
+	t.Parallel()
+
+	for _, in := range []string{
+		`{"a":1,"a":2}`,
+		`{"x":{"a":1,"a":1}}`,
+		`[1e400]`,
+		`-1e309`,
+		`{"a":`,
+		`[1,2`,
+		`1 2`,
+		``,
+	} {
+		_, err := nbid.NewJSON([]byte(in))
+		assert.True(t, errors.Is(err, nbid.ErrInvalidJSON), in)
+	}
+
+	_, err := nbid.NewJSON([]byte(`{"x":{"a":1,"a":1}}`))
+	assert.Contains(t, err.Error(), `duplicate key "a" in $.x`)
+
+	_, err = nbid.NewJSON([]byte(`{"x":[0,1e400]}`))
+	assert.Contains(t, err.Error(), `non-finite number 1e400`)
+	assert.Contains(t, err.Error(), `$.x[1]`)
+}
+
+func TestNewValue(t *testing.T) {
+	t.Parallel()
+
+	type order struct {
+		Tenant string  `json:"tenant"`
+		Number int     `json:"number"`
+		Total  float64 `json:"total"`
+	}
+
+	a, err := nbid.NewValue(order{Tenant: "acme", Number: 42, Total: 4.50})
+	assert.Nil(t, err)
+
+	b, err := nbid.NewValue(map[string]interface{}{"total": 4.5, "number": 42.0, "tenant": "acme"})
+	assert.Nil(t, err)
+
+	c, err := nbid.NewJSON([]byte(`{ "number": 4.2e1, "tenant": "acme", "total": 4.50 }`))
+	assert.Nil(t, err)
+
+	assert.Equal(t, a, b)
+	assert.Equal(t, a, c)
+
+	_, err = nbid.NewValue(math.NaN())
+	assert.True(t, errors.Is(err, nbid.ErrInvalidJSON))
+
+	_, err = nbid.NewValue(math.Inf(1))
+	assert.True(t, errors.Is(err, nbid.ErrInvalidJSON))
+}
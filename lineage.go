@@ -0,0 +1,87 @@
+// MIT License
+//
+// Copyright (c) 2021 Iván Szkiba
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package nbid
+
+import (
+	"errors"
+	"fmt"
+)
+
+// NamespaceLineage is the namespace of IDs returned by Derive.
+var NamespaceLineage = New([]byte("github.com/szkiba/nbid#lineage"))
+
+// ErrLineageMismatch is returned by Lineage.Verify if the recorded ID doesn't match the recomputed one.
+var ErrLineageMismatch = errors.New("nbid: lineage mismatch")
+
+// Derive returns the ID of the result of transformation op with params applied to parents.
+//
+// The ID is derived in NamespaceLineage (see NewNamespaced) from the field
+// encoding (see NewFields) of op as string, params as byte string, then parents
+// as NBIDs, so the same transformation of the same parents always yields the same ID.
+// The order of parents is significant.
+func Derive(op string, params []byte, parents ...NBID) NBID {
+	data := appendTagged(nil, tagString, []byte(op))
+	data = appendTagged(data, tagBytes, params)
+
+	for _, parent := range parents {
+		data = appendTagged(data, tagNBID, parent[:])
+	}
+
+	return NewNamespaced(NamespaceLineage, data)
+}
+
+// Lineage records how an ID was derived by Derive.
+//
+// Lineage records can be serialized as JSON (params are base64 encoded) and
+// verified later by recomputing the ID from the recorded parents.
+type Lineage struct {
+	ID      NBID   `json:"id"`
+	Op      string `json:"op"`
+	Params  []byte `json:"params,omitempty"`
+	Parents []NBID `json:"parents,omitempty"`
+}
+
+// NewLineage derives the ID of the result of transformation op with params
+// applied to parents and returns its lineage record.
+func NewLineage(op string, params []byte, parents ...NBID) *Lineage {
+	return &Lineage{
+		ID:      Derive(op, params, parents...),
+		Op:      op,
+		Params:  params,
+		Parents: parents,
+	}
+}
+
+// Derived recomputes the ID from the recorded transformation and parents.
+func (l *Lineage) Derived() NBID {
+	return Derive(l.Op, l.Params, l.Parents...)
+}
+
+// Verify returns ErrLineageMismatch if the recorded ID doesn't match the recomputed one.
+func (l *Lineage) Verify() error {
+	if derived := l.Derived(); derived != l.ID {
+		return fmt.Errorf("%w: recorded %s, derived %s", ErrLineageMismatch, l.ID, derived)
+	}
+
+	return nil
+}
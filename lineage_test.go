@@ -0,0 +1,77 @@
+// MIT License
+//
+// Copyright (c) 2021 Iván Szkiba
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package nbid_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/szkiba/nbid"
+)
+
+func TestDerive(t *testing.T) {
+	t.Parallel()
+
+	a := nbid.New([]byte("a"))
+	b := nbid.New([]byte("b"))
+	params := []byte(`{"on":"id"}`)
+
+	assert.Equal(t, "PQ0847I9LSFK6H0J1OL3LD8R08", nbid.Derive("join", params, a, b).String())
+	assert.Equal(t, "0H7PHCL0HJMK1TL4MN644IQTH4", nbid.Derive("import", nil).String())
+
+	assert.Equal(t, nbid.Derive("join", params, a, b), nbid.Derive("join", params, a, b))
+	assert.Equal(t, nbid.Derive("import", nil), nbid.Derive("import", []byte{}))
+	assert.NotEqual(t, nbid.Derive("join", params, a, b), nbid.Derive("join", params, b, a))
+	assert.NotEqual(t, nbid.Derive("join", params, a, b), nbid.Derive("join", nil, a, b))
+	assert.NotEqual(t, nbid.Derive("join", params, a, b), nbid.Derive("union", params, a, b))
+	assert.NotEqual(t, nbid.Derive("join", params, a), nbid.Derive("join", params, a, a))
+}
+
+func TestLineage(t *testing.T) {
+	t.Parallel()
+
+	a := nbid.New([]byte("a"))
+	b := nbid.New([]byte("b"))
+
+	l := nbid.NewLineage("join", []byte(`{"on":"id"}`), a, b)
+
+	assert.Equal(t, nbid.Derive("join", []byte(`{"on":"id"}`), a, b), l.ID)
+	assert.Nil(t, l.Verify())
+
+	data, err := json.Marshal(l)
+	assert.Nil(t, err)
+
+	var decoded nbid.Lineage
+
+	assert.Nil(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, *l, decoded)
+	assert.Nil(t, decoded.Verify())
+
+	decoded.Parents = []nbid.NBID{b, a}
+
+	err = decoded.Verify()
+	assert.True(t, errors.Is(err, nbid.ErrLineageMismatch))
+	assert.Contains(t, err.Error(), l.ID.String())
+}
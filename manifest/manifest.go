@@ -0,0 +1,256 @@
+// MIT License
+//
+// Copyright (c) 2021 Iván Szkiba
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Package manifest reads, writes and verifies checksum files listing the NBIDs
+// of file contents, in the layout of sha256sum checksum files.
+package manifest
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"strings"
+
+	"github.com/szkiba/nbid"
+)
+
+const idLen = 26 // length of the string form of NBID
+
+// ErrInvalid is returned when parsing a malformed manifest.
+var ErrInvalid = errors.New("manifest: invalid manifest")
+
+// Entry is a line of a manifest: the NBID of the content of a file and its path.
+type Entry struct {
+	ID   nbid.NBID
+	Path string
+}
+
+// Manifest is a list of file IDs in the layout of sha256sum checksum files:
+//
+//	QUKFNCO7QU098QEAJAUB021E9S  path/to/file
+//
+// Paths containing backslash or newline are escaped and the line is prefixed with
+// backslash, as sha256sum does. The binary mode marker (`*` before the path) is
+// accepted when parsing.
+type Manifest []Entry
+
+// Parse parses a manifest from r. Empty lines are ignored.
+func Parse(r io.Reader) (Manifest, error) {
+	var m Manifest
+
+	scanner := bufio.NewScanner(r)
+
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSuffix(scanner.Text(), "\r")
+		if line == "" {
+			continue
+		}
+
+		e, reason := parseLine(line)
+		if reason != "" {
+			return nil, fmt.Errorf("%w: line %d: %s", ErrInvalid, n, reason)
+		}
+
+		m = append(m, e)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// parseLine returns the entry of line, or the reason why it is invalid.
+func parseLine(line string) (Entry, string) {
+	escaped := strings.HasPrefix(line, `\`)
+	if escaped {
+		line = line[1:]
+	}
+
+	if len(line) < idLen+2 || line[idLen] != ' ' || (line[idLen+1] != ' ' && line[idLen+1] != '*') {
+		return Entry{}, "expected NBID, two spaces and path"
+	}
+
+	id, err := nbid.Parse(line[:idLen])
+	if err != nil {
+		return Entry{}, err.Error()
+	}
+
+	path := line[idLen+2:]
+
+	if escaped {
+		var ok bool
+
+		if path, ok = unescapePath(path); !ok {
+			return Entry{}, "invalid escape sequence"
+		}
+	}
+
+	if path == "" {
+		return Entry{}, "empty path"
+	}
+
+	return Entry{ID: id, Path: path}, ""
+}
+
+func unescapePath(s string) (string, bool) {
+	var b strings.Builder
+
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' {
+			b.WriteByte(s[i])
+
+			continue
+		}
+
+		if i++; i == len(s) {
+			return "", false
+		}
+
+		switch s[i] {
+		case '\\':
+			b.WriteByte('\\')
+		case 'n':
+			b.WriteByte('\n')
+		default:
+			return "", false
+		}
+	}
+
+	return b.String(), true
+}
+
+// WriteTo writes the manifest to w. It implements the io.WriterTo interface.
+func (m Manifest) WriteTo(w io.Writer) (int64, error) {
+	var total int64
+
+	for _, e := range m {
+		n, err := io.WriteString(w, e.String()+"\n")
+
+		total += int64(n)
+
+		if err != nil {
+			return total, err
+		}
+	}
+
+	return total, nil
+}
+
+// String returns the manifest line of the entry (without line terminator).
+func (e Entry) String() string {
+	if !strings.ContainsAny(e.Path, "\\\n") {
+		return e.ID.String() + "  " + e.Path
+	}
+
+	path := strings.NewReplacer(`\`, `\\`, "\n", `\n`).Replace(e.Path)
+
+	return `\` + e.ID.String() + "  " + path
+}
+
+// Status is the result of verifying a manifest entry.
+type Status int
+
+// Verification results.
+const (
+	// OK means the file content matches the ID.
+	OK Status = iota
+	// Failed means the file content doesn't match the ID, or it cannot be read.
+	Failed
+	// Missing means the file doesn't exist.
+	Missing
+)
+
+// String returns OK, FAILED or MISSING.
+func (s Status) String() string {
+	switch s {
+	case OK:
+		return "OK"
+	case Failed:
+		return "FAILED"
+	case Missing:
+		return "MISSING"
+	default:
+		return fmt.Sprintf("Status(%d)", int(s))
+	}
+}
+
+// Result is the result of verifying a manifest entry.
+type Result struct {
+	Entry  Entry
+	Status Status
+	// Err is the error opening or reading the file, if any.
+	Err error
+}
+
+// Check verifies the content of the files of the manifest in fsys.
+// The paths of the entries are passed to fsys as is.
+func (m Manifest) Check(fsys fs.FS) []Result {
+	results := make([]Result, len(m))
+
+	for i, e := range m {
+		results[i] = Result{Entry: e}
+
+		id, err := sumFile(fsys, e.Path)
+
+		switch {
+		case errors.Is(err, fs.ErrNotExist):
+			results[i].Status, results[i].Err = Missing, err
+		case err != nil:
+			results[i].Status, results[i].Err = Failed, err
+		case id != e.ID:
+			results[i].Status = Failed
+		}
+	}
+
+	return results
+}
+
+// New returns the manifest of the named files in fsys.
+func New(fsys fs.FS, names ...string) (Manifest, error) {
+	m := make(Manifest, 0, len(names))
+
+	for _, name := range names {
+		id, err := sumFile(fsys, name)
+		if err != nil {
+			return nil, err
+		}
+
+		m = append(m, Entry{ID: id, Path: name})
+	}
+
+	return m, nil
+}
+
+func sumFile(fsys fs.FS, name string) (nbid.NBID, error) {
+	f, err := fsys.Open(name)
+	if err != nil {
+		return nbid.Nil, err
+	}
+
+	defer f.Close() //nolint:errcheck
+
+	return nbid.NewReader(f)
+}
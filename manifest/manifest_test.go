@@ -0,0 +1,135 @@
+// MIT License
+//
+// Copyright (c) 2021 Iván Szkiba
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package manifest_test
+
+import (
+	"bytes"
+	"errors"
+	"io/fs"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/szkiba/nbid"
+	"github.com/szkiba/nbid/manifest"
+)
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	fsys := fstest.MapFS{
+		"fox.txt":       &fstest.MapFile{Data: []byte("The quick brown fox jumps over the lazy dog")},
+		"dir/empty.txt": &fstest.MapFile{},
+	}
+
+	m, err := manifest.New(fsys, "fox.txt", "dir/empty.txt")
+	assert.Nil(t, err)
+
+	var buf bytes.Buffer
+
+	n, err := m.WriteTo(&buf)
+
+	assert.Nil(t, err)
+	assert.Equal(t, int64(buf.Len()), n)
+	assert.Equal(t, "QUKFNCO7QU098QEAJAUB021E9S  fox.txt\nSEOC8GKOVGE196NRUJ49IRTP4G  dir/empty.txt\n", buf.String())
+
+	parsed, err := manifest.Parse(&buf)
+
+	assert.Nil(t, err)
+	assert.Equal(t, m, parsed)
+
+	_, err = manifest.New(fsys, "missing.txt")
+	assert.True(t, errors.Is(err, fs.ErrNotExist))
+}
+
+func TestEscape(t *testing.T) {
+	t.Parallel()
+
+	m := manifest.Manifest{{ID: nbid.Nil, Path: "a\\b\nc"}}
+
+	var buf bytes.Buffer
+
+	_, err := m.WriteTo(&buf)
+
+	assert.Nil(t, err)
+	assert.Equal(t, `\00000000000000000000000000  a\\b\nc`+"\n", buf.String())
+
+	parsed, err := manifest.Parse(&buf)
+
+	assert.Nil(t, err)
+	assert.Equal(t, m, parsed)
+}
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	m, err := manifest.Parse(strings.NewReader("\nQUKFNCO7QU098QEAJAUB021E9S *fox.txt\r\n\n"))
+
+	assert.Nil(t, err)
+	assert.Equal(t, manifest.Manifest{{ID: nbid.MustParse("QUKFNCO7QU098QEAJAUB021E9S"), Path: "fox.txt"}}, m)
+
+	for _, line := range []string{
+		"QUKFNCO7QU098QEAJAUB021E9S fox.txt",
+		"QUKFNCO7QU098QEAJAUB021E9S  ",
+		"QUKFNCO7QU098QEAJAUB021E9W  fox.txt",
+		"qukfnco7qu098qeajaub021e9s  fox.txt",
+		`\QUKFNCO7QU098QEAJAUB021E9S  fox\t.txt`,
+		`\QUKFNCO7QU098QEAJAUB021E9S  fox\`,
+	} {
+		_, err := manifest.Parse(strings.NewReader("QUKFNCO7QU098QEAJAUB021E9S  ok.txt\n" + line + "\n"))
+
+		assert.True(t, errors.Is(err, manifest.ErrInvalid), line)
+		assert.Contains(t, err.Error(), "line 2", line)
+	}
+}
+
+func TestCheck(t *testing.T) {
+	t.Parallel()
+
+	fsys := fstest.MapFS{
+		"ok.txt":      &fstest.MapFile{Data: []byte("ok")},
+		"changed.txt": &fstest.MapFile{Data: []byte("changed")},
+	}
+
+	m := manifest.Manifest{
+		{ID: nbid.New([]byte("ok")), Path: "ok.txt"},
+		{ID: nbid.New([]byte("original")), Path: "changed.txt"},
+		{ID: nbid.New([]byte("missing")), Path: "missing.txt"},
+	}
+
+	results := m.Check(fsys)
+
+	assert.Len(t, results, 3)
+	assert.Equal(t, manifest.OK, results[0].Status)
+	assert.Equal(t, manifest.Failed, results[1].Status)
+	assert.Nil(t, results[1].Err)
+	assert.Equal(t, manifest.Missing, results[2].Status)
+	assert.True(t, errors.Is(results[2].Err, fs.ErrNotExist))
+	assert.Equal(t, m[2], results[2].Entry)
+
+	assert.Equal(t, "OK", manifest.OK.String())
+	assert.Equal(t, "FAILED", manifest.Failed.String())
+	assert.Equal(t, "MISSING", manifest.Missing.String())
+	assert.Equal(t, "Status(42)", manifest.Status(42).String())
+}
@@ -0,0 +1,83 @@
+// MIT License
+//
+// Copyright (c) 2021 Iván Szkiba
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package nbid
+
+import (
+	"fmt"
+)
+
+// MsgpackExtType is the MessagePack extension type used for NBID ('N').
+const MsgpackExtType int8 = 'N'
+
+const (
+	msgpackFixExt16 = 0xd8
+	msgpackBin8     = 0xc4
+	msgpackStr8     = 0xd9
+	msgpackFixStr   = 0xa0
+	msgpackFixStrN  = 31
+)
+
+// MarshalMsgpack implements the github.com/vmihailenco/msgpack Marshaler interface.
+// NBID is encoded as a fixext 16 extension of type MsgpackExtType.
+func (id NBID) MarshalMsgpack() ([]byte, error) {
+	return append([]byte{msgpackFixExt16, byte(MsgpackExtType)}, id[:]...), nil
+}
+
+// UnmarshalMsgpack implements the github.com/vmihailenco/msgpack Unmarshaler interface.
+// It accepts a fixext 16 extension of type MsgpackExtType, a 16 byte long bin
+// or a 26 character long str (the string form).
+func (id *NBID) UnmarshalMsgpack(data []byte) error {
+	if len(data) == 0 {
+		return fmt.Errorf("%w: empty MessagePack data", ErrInvalidID)
+	}
+
+	switch {
+	case data[0] == msgpackFixExt16:
+		if len(data) != 2+rawLen || int8(data[1]) != MsgpackExtType {
+			return fmt.Errorf("%w: unexpected MessagePack extension", ErrInvalidID)
+		}
+
+		return id.UnmarshalBinary(data[2:])
+
+	case data[0] == msgpackBin8 || data[0] == msgpackStr8:
+		if len(data) < 2 || int(data[1]) != len(data)-2 {
+			return fmt.Errorf("%w: MessagePack length mismatch", ErrInvalidID)
+		}
+
+		if data[0] == msgpackBin8 {
+			return id.UnmarshalBinary(data[2:])
+		}
+
+		return id.UnmarshalText(data[2:])
+
+	case data[0]&^msgpackFixStrN == msgpackFixStr:
+		if int(data[0]&msgpackFixStrN) != len(data)-1 {
+			return fmt.Errorf("%w: MessagePack length mismatch", ErrInvalidID)
+		}
+
+		return id.UnmarshalText(data[1:])
+
+	default:
+		return fmt.Errorf("%w: unexpected MessagePack format 0x%02x", ErrInvalidID, data[0])
+	}
+}
@@ -0,0 +1,78 @@
+// MIT License
+//
+// Copyright (c) 2021 Iván Szkiba
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package nbid_test
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/szkiba/nbid"
+)
+
+func TestMsgpack(t *testing.T) {
+	t.Parallel()
+
+	id := nbid.MustParse(sampleText)
+
+	b, err := id.MarshalMsgpack()
+	assert.Nil(t, err)
+	assert.Equal(t, "d84e"+sampleHex, hex.EncodeToString(b))
+
+	tests := []struct {
+		name    string
+		hex     string
+		wantErr bool
+	}{
+		{name: "ext", hex: "d84e" + sampleHex},
+		{name: "bin", hex: "c410" + sampleHex},
+		{name: "fixstr", hex: "ba" + hex.EncodeToString([]byte(sampleText))},
+		{name: "str8", hex: "d91a" + hex.EncodeToString([]byte(sampleText))},
+		{name: "empty", hex: "", wantErr: true},
+		{name: "other_ext", hex: "d801" + sampleHex, wantErr: true},
+		{name: "short_bin", hex: "c410d7a8", wantErr: true},
+		{name: "short_str", hex: "a3414243", wantErr: true},
+		{name: "nil", hex: "c0", wantErr: true},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			data, err := hex.DecodeString(tt.hex)
+			assert.Nil(t, err)
+
+			var got nbid.NBID
+
+			err = got.UnmarshalMsgpack(data)
+			if tt.wantErr {
+				assert.Error(t, err)
+
+				return
+			}
+
+			assert.Nil(t, err)
+			assert.Equal(t, id, got)
+		})
+	}
+}
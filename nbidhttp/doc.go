@@ -0,0 +1,39 @@
+// MIT License
+//
+// Copyright (c) 2021 Iván Szkiba
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Package nbidhttp contains net/http helpers for NBIDs.
+//
+// The FromPath, FromQuery and FromHeader functions extract and validate an NBID
+// from the corresponding part of an incoming request. The Validate middleware
+// checks declared parameters up front and responds with an RFC 7807
+// problem+json error when any of them is missing or malformed, so handlers can
+// rely on the parameters being valid.
+//
+// The RequestIDs middleware tags every request with an NBID request ID available
+// via RequestID, and Transport propagates it to outbound requests.
+//
+// The ETags middleware tags responses with a strong ETag holding the NBID of the
+// response body and answers conditional requests.
+//
+// The Idempotent middleware stores the first response of a request, identified by
+// its Idempotency-Key header or Fingerprint, and replays it for duplicates.
+package nbidhttp
@@ -0,0 +1,182 @@
+// MIT License
+//
+// Copyright (c) 2021 Iván Szkiba
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package nbidhttp
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"hash"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/szkiba/nbid"
+)
+
+// ETagOptions configures the ETags middleware.
+type ETagOptions struct {
+	// ContentDigest enables the RFC 9530 Content-Digest response header
+	// (sha-256 of the response body).
+	ContentDigest bool
+}
+
+// ETags returns a middleware that sets a strong ETag derived from the response body.
+//
+// The body is streamed through SHA256 while it is buffered, and the ETag is the
+// quoted NBID of the body, the same as nbid.New would return for it. Only
+// successful (2xx) responses of GET and HEAD requests are tagged, and an ETag
+// set by the handler is left intact. HEAD responses are tagged only if the handler
+// wrote the body (net/http discards it), because handlers omitting the body of HEAD
+// responses (like http.ServeContent) would get the ETag of the empty body instead
+// of the ETag of the GET response.
+//
+// Conditional requests are answered after the handler has run:
+// a matching If-None-Match results in 304 Not Modified, a non-matching
+// If-Match results in 412 Precondition Failed.
+func ETags(opts ETagOptions) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodGet && r.Method != http.MethodHead {
+				next.ServeHTTP(w, r)
+
+				return
+			}
+
+			bw := &bufferedWriter{ResponseWriter: w, hash: sha256.New(), status: http.StatusOK}
+
+			next.ServeHTTP(bw, r)
+
+			bw.finish(r, opts)
+		})
+	}
+}
+
+type bufferedWriter struct {
+	http.ResponseWriter
+	hash   hash.Hash
+	buf    bytes.Buffer
+	status int
+	wrote  bool
+}
+
+func (w *bufferedWriter) WriteHeader(status int) {
+	w.status = status
+}
+
+func (w *bufferedWriter) Write(b []byte) (int, error) {
+	w.hash.Write(b) //nolint:errcheck
+	w.wrote = w.wrote || len(b) != 0
+
+	return w.buf.Write(b)
+}
+
+func (w *bufferedWriter) finish(r *http.Request, opts ETagOptions) {
+	header := w.Header()
+
+	if w.status < 200 || w.status >= 300 {
+		w.flush()
+
+		return
+	}
+
+	if r.Method == http.MethodHead && !w.wrote {
+		w.ResponseWriter.WriteHeader(w.status)
+
+		return
+	}
+
+	sum := w.hash.Sum(nil)
+
+	if header.Get("ETag") == "" {
+		id, _ := nbid.FromBytes(sum[:16])
+		header.Set("ETag", `"`+id.String()+`"`)
+	}
+
+	if opts.ContentDigest {
+		header.Set("Content-Digest", "sha-256=:"+base64.StdEncoding.EncodeToString(sum)+":")
+	}
+
+	etag := header.Get("ETag")
+
+	if im := r.Header.Get("If-Match"); im != "" && !matchETag(im, etag, false) {
+		w.discard()
+		w.ResponseWriter.WriteHeader(http.StatusPreconditionFailed)
+
+		return
+	}
+
+	if inm := r.Header.Get("If-None-Match"); inm != "" && matchETag(inm, etag, true) {
+		w.discard()
+		w.ResponseWriter.WriteHeader(http.StatusNotModified)
+
+		return
+	}
+
+	w.flush()
+}
+
+func (w *bufferedWriter) discard() {
+	header := w.Header()
+
+	header.Del("Content-Length")
+	header.Del("Content-Type")
+	header.Del("Content-Digest")
+}
+
+func (w *bufferedWriter) flush() {
+	if w.Header().Get("Content-Length") == "" {
+		w.Header().Set("Content-Length", strconv.Itoa(w.buf.Len()))
+	}
+
+	w.ResponseWriter.WriteHeader(w.status)
+	w.ResponseWriter.Write(w.buf.Bytes()) //nolint:errcheck
+}
+
+// matchETag reports whether etag matches any entity tag of the comma separated
+// list. Weak comparison ignores the W/ prefix, strong comparison never matches weak tags.
+func matchETag(list string, etag string, weak bool) bool {
+	if strings.TrimSpace(list) == "*" {
+		return true
+	}
+
+	if weak {
+		etag = strings.TrimPrefix(etag, "W/")
+	} else if strings.HasPrefix(etag, "W/") {
+		return false
+	}
+
+	for _, tag := range strings.Split(list, ",") {
+		tag = strings.TrimSpace(tag)
+
+		if weak {
+			tag = strings.TrimPrefix(tag, "W/")
+		}
+
+		if tag == etag {
+			return true
+		}
+	}
+
+	return false
+}
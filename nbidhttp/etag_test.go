@@ -0,0 +1,169 @@
+// MIT License
+//
+// Copyright (c) 2021 Iván Szkiba
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package nbidhttp_test
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/szkiba/nbid"
+	"github.com/szkiba/nbid/nbidhttp"
+)
+
+const body = "The quick brown fox jumps over the lazy dog"
+
+func TestETags(t *testing.T) {
+	t.Parallel()
+
+	h := nbidhttp.ETags(nbidhttp.ETagOptions{ContentDigest: true})(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/missing" {
+				http.NotFound(w, r)
+
+				return
+			}
+
+			w.Header().Set("Content-Type", "text/plain")
+			w.Write([]byte(body[:10])) //nolint:errcheck
+			w.Write([]byte(body[10:])) //nolint:errcheck
+		}))
+
+	etag := `"` + nbid.New([]byte(body)).String() + `"`
+	sum := sha256.Sum256([]byte(body))
+	digest := "sha-256=:" + base64.StdEncoding.EncodeToString(sum[:]) + ":"
+
+	tests := []struct {
+		name     string
+		method   string
+		path     string
+		header   map[string]string
+		status   int
+		body     string
+		noETag   bool
+		noDigest bool
+	}{
+		{name: "normal", status: http.StatusOK, body: body},
+		{name: "if_none_match", header: map[string]string{"If-None-Match": `"X", ` + etag}, status: http.StatusNotModified, noDigest: true},
+		{name: "if_none_match_weak", header: map[string]string{"If-None-Match": "W/" + etag}, status: http.StatusNotModified, noDigest: true},
+		{name: "if_none_match_other", header: map[string]string{"If-None-Match": `"X"`}, status: http.StatusOK, body: body},
+		{name: "if_match", header: map[string]string{"If-Match": etag}, status: http.StatusOK, body: body},
+		{name: "if_match_any", header: map[string]string{"If-Match": "*"}, status: http.StatusOK, body: body},
+		{name: "if_match_weak", header: map[string]string{"If-Match": "W/" + etag}, status: http.StatusPreconditionFailed, noDigest: true},
+		{name: "if_match_other", header: map[string]string{"If-Match": `"X"`}, status: http.StatusPreconditionFailed, noDigest: true},
+		{name: "not_found", path: "/missing", status: http.StatusNotFound, body: "404 page not found\n", noETag: true, noDigest: true},
+		{name: "post", method: http.MethodPost, status: http.StatusOK, body: body, noETag: true, noDigest: true},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			method, path := tt.method, tt.path
+			if method == "" {
+				method = http.MethodGet
+			}
+
+			if path == "" {
+				path = "/"
+			}
+
+			r := httptest.NewRequest(method, path, nil)
+			for k, v := range tt.header {
+				r.Header.Set(k, v)
+			}
+
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+
+			assert.Equal(t, tt.status, w.Code)
+			assert.Equal(t, tt.body, w.Body.String())
+
+			if tt.noETag {
+				assert.Empty(t, w.Header().Get("ETag"))
+			} else {
+				assert.Equal(t, etag, w.Header().Get("ETag"))
+			}
+
+			if tt.noDigest {
+				assert.Empty(t, w.Header().Get("Content-Digest"))
+			} else {
+				assert.Equal(t, digest, w.Header().Get("Content-Digest"))
+			}
+		})
+	}
+}
+
+func TestETagsHead(t *testing.T) {
+	t.Parallel()
+
+	modtime := time.Date(2021, 3, 14, 12, 0, 0, 0, time.UTC)
+
+	// serve_content omits the body of HEAD responses, so they can't be tagged
+	tagged := map[string]bool{"write": true, "serve_content": false}
+
+	handlers := map[string]http.Handler{
+		"write": http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(body)) //nolint:errcheck
+		}),
+		"serve_content": http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.ServeContent(w, r, "fox.txt", modtime, strings.NewReader(body))
+		}),
+	}
+
+	etag := `"` + nbid.New([]byte(body)).String() + `"`
+
+	for name, handler := range handlers {
+		h := nbidhttp.ETags(nbidhttp.ETagOptions{})(handler)
+
+		get := httptest.NewRecorder()
+		h.ServeHTTP(get, httptest.NewRequest(http.MethodGet, "/", nil))
+
+		head := httptest.NewRecorder()
+		h.ServeHTTP(head, httptest.NewRequest(http.MethodHead, "/", nil))
+
+		assert.Equal(t, etag, get.Header().Get("ETag"), name)
+		assert.Equal(t, http.StatusOK, head.Code, name)
+		assert.Equal(t, strconv.Itoa(len(body)), head.Header().Get("Content-Length"), name)
+
+		if !tagged[name] {
+			assert.Empty(t, head.Header().Get("ETag"), name)
+		} else {
+			assert.Equal(t, etag, head.Header().Get("ETag"), name)
+
+			r := httptest.NewRequest(http.MethodHead, "/", nil)
+			r.Header.Set("If-None-Match", etag)
+
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+
+			assert.Equal(t, http.StatusNotModified, w.Code, name)
+		}
+	}
+}
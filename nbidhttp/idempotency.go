@@ -0,0 +1,374 @@
+// MIT License
+//
+// Copyright (c) 2021 Iván Szkiba
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package nbidhttp
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"sync"
+
+	"github.com/szkiba/nbid"
+)
+
+// HeaderIdempotencyKey is the header carrying a client supplied idempotency key.
+const HeaderIdempotencyKey = "Idempotency-Key"
+
+// DefaultMaxBodyBytes is the default limit of request bodies read by the Idempotent middleware.
+const DefaultMaxBodyBytes = 1 << 20
+
+var (
+	// ErrInFlight is returned by Store.Begin when a request with the same key is being processed.
+	ErrInFlight = errors.New("nbidhttp: request in flight")
+
+	// ErrKeyReused is returned by Store.Begin when a key is reused with a different fingerprint.
+	ErrKeyReused = errors.New("nbidhttp: idempotency key reused with different request")
+
+	// ErrUnknownKey is returned by Store.Complete for keys without Begin.
+	ErrUnknownKey = errors.New("nbidhttp: unknown idempotency key")
+)
+
+// StoredResponse is a recorded response that can be replayed.
+type StoredResponse struct {
+	Status int
+	Header http.Header
+	Body   []byte
+}
+
+// Store keeps the first response of idempotent requests.
+// Implementations must be safe for concurrent use.
+type Store interface {
+	// Begin starts processing of the request identified by key and fingerprint.
+	// It returns the stored response if the request has been completed already,
+	// ErrInFlight if it is being processed, ErrKeyReused if key belongs to a request
+	// with a different fingerprint, or nil response and nil error if the caller
+	// should process the request.
+	Begin(key nbid.NBID, fingerprint nbid.NBID) (*StoredResponse, error)
+
+	// Complete stores the response of the request identified by key.
+	Complete(key nbid.NBID, resp *StoredResponse) error
+
+	// Abort forgets the in-flight request identified by key, so it can be retried.
+	Abort(key nbid.NBID) error
+}
+
+type memoryEntry struct {
+	fingerprint nbid.NBID
+	resp        *StoredResponse
+}
+
+// MemoryStore is an in-memory Store. Entries are never evicted.
+type MemoryStore struct {
+	mu      sync.Mutex
+	entries map[nbid.NBID]*memoryEntry
+}
+
+// NewMemoryStore returns a new, empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{entries: make(map[nbid.NBID]*memoryEntry)}
+}
+
+// Begin implements Store.
+func (s *MemoryStore) Begin(key nbid.NBID, fingerprint nbid.NBID) (*StoredResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.entries[key]
+	if !ok {
+		s.entries[key] = &memoryEntry{fingerprint: fingerprint}
+
+		return nil, nil
+	}
+
+	if !entry.fingerprint.Equal(fingerprint) {
+		return nil, ErrKeyReused
+	}
+
+	if entry.resp == nil {
+		return nil, ErrInFlight
+	}
+
+	return entry.resp, nil
+}
+
+// Complete implements Store.
+func (s *MemoryStore) Complete(key nbid.NBID, resp *StoredResponse) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.entries[key]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownKey, key)
+	}
+
+	entry.resp = resp
+
+	return nil
+}
+
+// Abort implements Store.
+func (s *MemoryStore) Abort(key nbid.NBID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.entries, key)
+
+	return nil
+}
+
+// Fingerprint returns an NBID identifying the request by its method, path and
+// query (the request URI), the given headers and body. The body is read and replaced, so it can be read again.
+// Each part (including each value of the headers) is length prefixed before
+// hashing, so different requests cannot produce the same input.
+// The whole body is read, use http.MaxBytesReader to limit its size.
+func Fingerprint(r *http.Request, headers ...string) (nbid.NBID, error) {
+	var body []byte
+
+	if r.Body != nil {
+		var err error
+
+		body, err = io.ReadAll(r.Body)
+		if err != nil {
+			return nbid.Nil, err
+		}
+
+		r.Body.Close()
+		r.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	names := make([]string, len(headers))
+	for i, name := range headers {
+		names[i] = http.CanonicalHeaderKey(name)
+	}
+
+	sort.Strings(names)
+
+	h := sha256.New()
+
+	writeLen := func(l int) {
+		var n [8]byte
+
+		binary.BigEndian.PutUint64(n[:], uint64(l))
+		h.Write(n[:]) //nolint:errcheck
+	}
+
+	writePart := func(b []byte) {
+		writeLen(len(b))
+		h.Write(b) //nolint:errcheck
+	}
+
+	writePart([]byte(r.Method))
+	writePart([]byte(r.URL.RequestURI()))
+
+	for _, name := range names {
+		values := r.Header.Values(name)
+
+		writePart([]byte(name))
+		writeLen(len(values))
+
+		for _, value := range values {
+			writePart([]byte(value))
+		}
+	}
+
+	writePart(body)
+
+	return nbid.FromBytes(h.Sum(nil)[:16])
+}
+
+// IdempotencyOptions configures the Idempotent middleware.
+type IdempotencyOptions struct {
+	// Store keeps the responses. Defaults to a new MemoryStore.
+	Store Store
+
+	// Headers lists the request headers included in the fingerprint.
+	Headers []string
+
+	// Methods lists the request methods handled. Defaults to POST only.
+	Methods []string
+
+	// MaxBodyBytes limits the size of request bodies read for the fingerprint.
+	// Defaults to DefaultMaxBodyBytes, a negative value disables the limit.
+	MaxBodyBytes int64
+}
+
+// Idempotent returns a middleware that makes requests idempotent.
+//
+// Requests are identified by the Idempotency-Key header (which must hold an
+// NBID), or by their Fingerprint if the header is missing. The first response
+// for a key is stored and replayed for subsequent requests with the same key.
+// Concurrent duplicates are rejected with 409 Conflict, keys reused for different
+// requests with 422 Unprocessable Entity, malformed keys with 400 Bad Request and
+// bodies larger than MaxBodyBytes with 413 Request Entity Too Large.
+// Server errors (5xx) are not stored, so the request can be retried.
+func Idempotent(opts IdempotencyOptions) func(http.Handler) http.Handler {
+	store := opts.Store
+	if store == nil {
+		store = NewMemoryStore()
+	}
+
+	methods := opts.Methods
+	if len(methods) == 0 {
+		methods = []string{http.MethodPost}
+	}
+
+	maxBodyBytes := opts.MaxBodyBytes
+	if maxBodyBytes == 0 {
+		maxBodyBytes = DefaultMaxBodyBytes
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !contains(methods, r.Method) {
+				next.ServeHTTP(w, r)
+
+				return
+			}
+
+			if maxBodyBytes > 0 && r.Body != nil {
+				r.Body = http.MaxBytesReader(w, r.Body, maxBodyBytes)
+			}
+
+			fingerprint, err := Fingerprint(r, opts.Headers...)
+			if err != nil {
+				status := http.StatusBadRequest
+
+				var merr *http.MaxBytesError
+				if errors.As(err, &merr) {
+					status = http.StatusRequestEntityTooLarge
+				}
+
+				WriteProblem(w, r, NewProblem(status, err))
+
+				return
+			}
+
+			key := fingerprint
+
+			if r.Header.Get(HeaderIdempotencyKey) != "" {
+				if key, err = FromHeader(r, HeaderIdempotencyKey); err != nil {
+					WriteProblem(w, r, NewProblem(http.StatusBadRequest, err))
+
+					return
+				}
+			}
+
+			resp, err := store.Begin(key, fingerprint)
+
+			switch {
+			case errors.Is(err, ErrInFlight):
+				WriteProblem(w, r, NewProblem(http.StatusConflict, err))
+			case errors.Is(err, ErrKeyReused):
+				WriteProblem(w, r, NewProblem(http.StatusUnprocessableEntity, err))
+			case err != nil:
+				WriteProblem(w, r, NewProblem(http.StatusInternalServerError, err))
+			case resp != nil:
+				replay(w, resp)
+			default:
+				serve(w, r, next, store, key)
+			}
+		})
+	}
+}
+
+func serve(w http.ResponseWriter, r *http.Request, next http.Handler, store Store, key nbid.NBID) {
+	rec := &recordingWriter{ResponseWriter: w, status: http.StatusOK}
+
+	defer func() {
+		if p := recover(); p != nil {
+			store.Abort(key) //nolint:errcheck
+			panic(p)
+		}
+	}()
+
+	next.ServeHTTP(rec, r)
+
+	if rec.status >= http.StatusInternalServerError {
+		store.Abort(key) //nolint:errcheck
+
+		return
+	}
+
+	if rec.header == nil {
+		rec.header = w.Header().Clone()
+	}
+
+	store.Complete(key, &StoredResponse{ //nolint:errcheck
+		Status: rec.status,
+		Header: rec.header,
+		Body:   rec.body.Bytes(),
+	})
+}
+
+func replay(w http.ResponseWriter, resp *StoredResponse) {
+	for k, v := range resp.Header {
+		w.Header()[k] = v
+	}
+
+	w.WriteHeader(resp.Status)
+	w.Write(resp.Body) //nolint:errcheck
+}
+
+type recordingWriter struct {
+	http.ResponseWriter
+	status      int
+	header      http.Header
+	body        bytes.Buffer
+	wroteHeader bool
+}
+
+func (w *recordingWriter) WriteHeader(status int) {
+	if w.wroteHeader {
+		return
+	}
+
+	w.wroteHeader = true
+	w.status = status
+	w.header = w.Header().Clone()
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *recordingWriter) Write(b []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+
+	w.body.Write(b)
+
+	return w.ResponseWriter.Write(b)
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,261 @@
+// MIT License
+//
+// Copyright (c) 2021 Iván Szkiba
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package nbidhttp_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/szkiba/nbid"
+	"github.com/szkiba/nbid/nbidhttp"
+)
+
+func TestFingerprint(t *testing.T) {
+	t.Parallel()
+
+	newRequest := func(path, body, tenant string) *http.Request {
+		r := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+		r.Header.Set("X-Tenant", tenant)
+
+		return r
+	}
+
+	a, err := nbidhttp.Fingerprint(newRequest("/pay", "42", "t1"), "X-Tenant")
+	assert.Nil(t, err)
+
+	b, err := nbidhttp.Fingerprint(newRequest("/pay", "42", "t1"), "x-tenant")
+	assert.Nil(t, err)
+	assert.Equal(t, a, b)
+
+	c, err := nbidhttp.Fingerprint(newRequest("/pay", "42", "t2"), "X-Tenant")
+	assert.Nil(t, err)
+	assert.NotEqual(t, a, c)
+
+	d, err := nbidhttp.Fingerprint(newRequest("/pay4", "2", "t1"), "X-Tenant")
+	assert.Nil(t, err)
+	assert.NotEqual(t, a, d)
+
+	e, err := nbidhttp.Fingerprint(newRequest("/pay?amount=100", "42", "t1"), "X-Tenant")
+	assert.Nil(t, err)
+
+	f, err := nbidhttp.Fingerprint(newRequest("/pay?amount=200", "42", "t1"), "X-Tenant")
+	assert.Nil(t, err)
+	assert.NotEqual(t, a, e)
+	assert.NotEqual(t, e, f)
+
+	// header values are length prefixed one by one
+	g := newRequest("/pay", "42", "t1,t2")
+	h := newRequest("/pay", "42", "t1")
+	h.Header.Add("X-Tenant", "t2")
+
+	fg, err := nbidhttp.Fingerprint(g, "X-Tenant")
+	assert.Nil(t, err)
+
+	fh, err := nbidhttp.Fingerprint(h, "X-Tenant")
+	assert.Nil(t, err)
+	assert.NotEqual(t, fg, fh)
+
+	// values of one header cannot be shifted to the next one
+	i := newRequest("/pay", "42", "X-Zone")
+	j := newRequest("/pay", "42", "")
+	j.Header.Del("X-Tenant")
+	j.Header.Set("X-Zone", "X-Zone")
+
+	fi, err := nbidhttp.Fingerprint(i, "X-Tenant", "X-Zone")
+	assert.Nil(t, err)
+
+	fj, err := nbidhttp.Fingerprint(j, "X-Tenant", "X-Zone")
+	assert.Nil(t, err)
+	assert.NotEqual(t, fi, fj)
+
+	// body can be read again
+	r := newRequest("/pay", "42", "t1")
+	_, err = nbidhttp.Fingerprint(r)
+	assert.Nil(t, err)
+
+	body, err := io.ReadAll(r.Body)
+	assert.Nil(t, err)
+	assert.Equal(t, "42", string(body))
+}
+
+func TestIdempotent(t *testing.T) {
+	t.Parallel()
+
+	var calls int32
+
+	h := nbidhttp.Idempotent(nbidhttp.IdempotencyOptions{})(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			n := atomic.AddInt32(&calls, 1)
+
+			w.Header().Set("X-Call", string(rune('0'+n)))
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte("paid")) //nolint:errcheck
+		}))
+
+	do := func(body, key string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodPost, "/pay", strings.NewReader(body))
+		if key != "" {
+			r.Header.Set(nbidhttp.HeaderIdempotencyKey, key)
+		}
+
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+
+		return w
+	}
+
+	// fingerprint based
+	w := do("42", "")
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, "1", w.Header().Get("X-Call"))
+
+	w = do("42", "")
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, "paid", w.Body.String())
+	assert.Equal(t, "1", w.Header().Get("X-Call"))
+
+	// client key based
+	key := nbid.Random().String()
+
+	w = do("43", key)
+	assert.Equal(t, "2", w.Header().Get("X-Call"))
+
+	w = do("43", key)
+	assert.Equal(t, "2", w.Header().Get("X-Call"))
+
+	w = do("44", key)
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+
+	w = do("43", "XXX")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+}
+
+func TestIdempotentQuery(t *testing.T) {
+	t.Parallel()
+
+	var calls int32
+
+	h := nbidhttp.Idempotent(nbidhttp.IdempotencyOptions{})(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&calls, 1)
+			w.Write([]byte("charged " + r.URL.Query().Get("amount"))) //nolint:errcheck
+		}))
+
+	for _, amount := range []string{"100", "200", "100"} {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/pay?amount="+amount, nil))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "charged "+amount, w.Body.String())
+	}
+
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+}
+
+func TestIdempotentMaxBodyBytes(t *testing.T) {
+	t.Parallel()
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.Write(body) //nolint:errcheck
+	})
+
+	do := func(opts nbidhttp.IdempotencyOptions, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		nbidhttp.Idempotent(opts)(handler).ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/pay", strings.NewReader(body)))
+
+		return w
+	}
+
+	w := do(nbidhttp.IdempotencyOptions{MaxBodyBytes: 4}, "1234")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "1234", w.Body.String())
+
+	w = do(nbidhttp.IdempotencyOptions{MaxBodyBytes: 4}, "12345")
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+
+	large := strings.Repeat("x", nbidhttp.DefaultMaxBodyBytes+1)
+
+	w = do(nbidhttp.IdempotencyOptions{}, large)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+
+	w = do(nbidhttp.IdempotencyOptions{MaxBodyBytes: -1}, large)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, len(large), w.Body.Len())
+}
+
+func TestIdempotentInFlight(t *testing.T) {
+	t.Parallel()
+
+	entered := make(chan struct{})
+	release := make(chan struct{})
+
+	h := nbidhttp.Idempotent(nbidhttp.IdempotencyOptions{})(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			close(entered)
+			<-release
+		}))
+
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/pay", strings.NewReader("42")))
+	}()
+
+	<-entered
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/pay", strings.NewReader("42")))
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	close(release)
+	<-done
+}
+
+func TestIdempotentServerError(t *testing.T) {
+	t.Parallel()
+
+	var calls int32
+
+	h := nbidhttp.Idempotent(nbidhttp.IdempotencyOptions{})(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&calls, 1)
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+
+	for i := 0; i < 2; i++ {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/pay", strings.NewReader("42")))
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	}
+
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+}
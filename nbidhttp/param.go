@@ -0,0 +1,135 @@
+// MIT License
+//
+// Copyright (c) 2021 Iván Szkiba
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package nbidhttp
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/szkiba/nbid"
+)
+
+// ErrMissing is returned when a requested parameter is not present in the request.
+var ErrMissing = errors.New("nbidhttp: missing parameter")
+
+// Location identifies the part of the request a parameter is read from.
+type Location string
+
+const (
+	// InPath refers to a wildcard of the http.ServeMux route pattern.
+	InPath Location = "path"
+	// InQuery refers to a URL query parameter.
+	InQuery Location = "query"
+	// InHeader refers to a request header.
+	InHeader Location = "header"
+)
+
+// Param declares a request parameter holding an NBID.
+type Param struct {
+	In       Location
+	Name     string
+	Optional bool // Optional parameters may be missing, but must be valid if present
+}
+
+// ParamError is returned when a parameter is missing or cannot be parsed.
+// Err is either ErrMissing or a *nbid.ParseError.
+type ParamError struct {
+	In   Location
+	Name string
+	Err  error
+}
+
+func (e *ParamError) Error() string {
+	return fmt.Sprintf("nbidhttp: %s parameter %q: %v", e.In, e.Name, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *ParamError) Unwrap() error {
+	return e.Err
+}
+
+// FromPath returns the NBID from the named path wildcard of the request.
+// It uses http.Request.PathValue, so the request should be routed by http.ServeMux.
+func FromPath(r *http.Request, name string) (nbid.NBID, error) {
+	return parse(InPath, name, r.PathValue(name))
+}
+
+// FromQuery returns the NBID from the named URL query parameter of the request.
+func FromQuery(r *http.Request, name string) (nbid.NBID, error) {
+	return parse(InQuery, name, r.URL.Query().Get(name))
+}
+
+// FromHeader returns the NBID from the named header of the request.
+func FromHeader(r *http.Request, name string) (nbid.NBID, error) {
+	return parse(InHeader, name, r.Header.Get(name))
+}
+
+// From returns the NBID of the declared parameter p.
+func From(r *http.Request, p Param) (nbid.NBID, error) {
+	switch p.In {
+	case InPath:
+		return FromPath(r, p.Name)
+	case InQuery:
+		return FromQuery(r, p.Name)
+	case InHeader:
+		return FromHeader(r, p.Name)
+	default:
+		return nbid.Nil, &ParamError{In: p.In, Name: p.Name, Err: fmt.Errorf("unknown location %q", p.In)}
+	}
+}
+
+func parse(in Location, name string, value string) (nbid.NBID, error) {
+	if value == "" {
+		return nbid.Nil, &ParamError{In: in, Name: name, Err: ErrMissing}
+	}
+
+	id, err := nbid.Parse(value)
+	if err != nil {
+		return nbid.Nil, &ParamError{In: in, Name: name, Err: err}
+	}
+
+	return id, nil
+}
+
+// Validate returns a middleware that validates the declared parameters before
+// calling the next handler. On the first invalid parameter it responds with
+// 400 Bad Request and an RFC 7807 problem+json body built from the error.
+func Validate(params ...Param) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			for _, p := range params {
+				_, err := From(r, p)
+				if err == nil || (p.Optional && errors.Is(err, ErrMissing)) {
+					continue
+				}
+
+				WriteProblem(w, r, NewProblem(http.StatusBadRequest, err))
+
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
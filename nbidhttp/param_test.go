@@ -0,0 +1,164 @@
+// MIT License
+//
+// Copyright (c) 2021 Iván Szkiba
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package nbidhttp_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/szkiba/nbid"
+	"github.com/szkiba/nbid/nbidhttp"
+)
+
+const sample = "QUKFNCO7QU098QEAJAUB021E9S"
+
+func TestFromPath(t *testing.T) {
+	t.Parallel()
+
+	var (
+		got nbid.NBID
+		err error
+	)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/users/{id}", func(w http.ResponseWriter, r *http.Request) {
+		got, err = nbidhttp.FromPath(r, "id")
+	})
+
+	mux.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/users/"+sample, nil))
+	assert.Nil(t, err)
+	assert.Equal(t, nbid.MustParse(sample), got)
+
+	mux.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/users/XXX", nil))
+	assert.True(t, errors.Is(err, nbid.ErrInvalidID))
+}
+
+func TestFromQueryAndHeader(t *testing.T) {
+	t.Parallel()
+
+	r := httptest.NewRequest(http.MethodGet, "/?owner="+sample+"&bad=XXX", nil)
+	r.Header.Set("X-Tenant", sample)
+
+	id, err := nbidhttp.FromQuery(r, "owner")
+	assert.Nil(t, err)
+	assert.Equal(t, nbid.MustParse(sample), id)
+
+	id, err = nbidhttp.FromHeader(r, "X-Tenant")
+	assert.Nil(t, err)
+	assert.Equal(t, nbid.MustParse(sample), id)
+
+	_, err = nbidhttp.FromQuery(r, "bad")
+
+	var perr *nbidhttp.ParamError
+
+	assert.True(t, errors.As(err, &perr))
+	assert.Equal(t, nbidhttp.InQuery, perr.In)
+	assert.Equal(t, "bad", perr.Name)
+
+	_, err = nbidhttp.FromHeader(r, "X-Missing")
+	assert.True(t, errors.Is(err, nbidhttp.ErrMissing))
+}
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+
+	validate := nbidhttp.Validate(
+		nbidhttp.Param{In: nbidhttp.InPath, Name: "id"},
+		nbidhttp.Param{In: nbidhttp.InQuery, Name: "owner", Optional: true},
+	)
+
+	mux := http.NewServeMux()
+	mux.Handle("/users/{id}", validate(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})))
+
+	tests := []struct {
+		name   string
+		target string
+		status int
+		detail string
+		param  string
+	}{
+		{name: "normal", target: "/users/" + sample, status: http.StatusNoContent},
+		{name: "optional", target: "/users/" + sample + "?owner=" + sample, status: http.StatusNoContent},
+		{
+			name:   "invalid_path",
+			target: "/users/XXX",
+			status: http.StatusBadRequest,
+			detail: `invalid path parameter "id": invalid length 3, expected 26`,
+			param:  "id",
+		},
+		{
+			name:   "invalid_query",
+			target: "/users/" + sample + "?owner=ABCDEFGHIJKLMNOPQRSTUVWXYZ",
+			status: http.StatusBadRequest,
+			detail: `invalid query parameter "owner": illegal character 'W' at offset 22`,
+			param:  "owner",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.target, nil))
+
+			assert.Equal(t, tt.status, w.Code)
+
+			if tt.status != http.StatusBadRequest {
+				return
+			}
+
+			assert.Equal(t, nbidhttp.ContentTypeProblem, w.Header().Get("Content-Type"))
+
+			var p nbidhttp.Problem
+
+			assert.Nil(t, json.NewDecoder(w.Body).Decode(&p))
+			assert.Equal(t, http.StatusBadRequest, p.Status)
+			assert.Equal(t, "Bad Request", p.Title)
+			assert.Equal(t, tt.detail, p.Detail)
+			assert.Equal(t, tt.param, p.Param)
+		})
+	}
+}
+
+func TestValidateMissing(t *testing.T) {
+	t.Parallel()
+
+	h := nbidhttp.Validate(nbidhttp.Param{In: nbidhttp.InHeader, Name: "X-Tenant"})(http.NotFoundHandler())
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/orders", nil))
+
+	var p nbidhttp.Problem
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Nil(t, json.NewDecoder(w.Body).Decode(&p))
+	assert.Equal(t, `missing header parameter "X-Tenant"`, p.Detail)
+	assert.Equal(t, "/orders", p.Instance)
+}
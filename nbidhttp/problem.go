@@ -0,0 +1,92 @@
+// MIT License
+//
+// Copyright (c) 2021 Iván Szkiba
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package nbidhttp
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/szkiba/nbid"
+)
+
+// ContentTypeProblem is the media type of RFC 7807 problem details.
+const ContentTypeProblem = "application/problem+json"
+
+// Problem is an RFC 7807 problem details object.
+// The Param and In extension members identify the offending parameter.
+type Problem struct {
+	Type     string   `json:"type,omitempty"`
+	Title    string   `json:"title"`
+	Status   int      `json:"status"`
+	Detail   string   `json:"detail,omitempty"`
+	Instance string   `json:"instance,omitempty"`
+	In       Location `json:"in,omitempty"`
+	Param    string   `json:"param,omitempty"`
+}
+
+// NewProblem builds a Problem with the given status from err.
+// If err is a *ParamError, the detail is built from the missing parameter or
+// from the reason of the underlying *nbid.ParseError.
+func NewProblem(status int, err error) *Problem {
+	p := &Problem{
+		Type:   "about:blank",
+		Title:  http.StatusText(status),
+		Status: status,
+		Detail: err.Error(),
+	}
+
+	var perr *ParamError
+	if !errors.As(err, &perr) {
+		return p
+	}
+
+	p.In = perr.In
+	p.Param = perr.Name
+
+	var parseErr *nbid.ParseError
+
+	switch {
+	case errors.Is(perr.Err, ErrMissing):
+		p.Detail = fmt.Sprintf("missing %s parameter %q", perr.In, perr.Name)
+	case errors.As(perr.Err, &parseErr):
+		p.Detail = fmt.Sprintf("invalid %s parameter %q: %s", perr.In, perr.Name, parseErr.Reason)
+	}
+
+	return p
+}
+
+// WriteProblem writes p as an application/problem+json response.
+// The Instance member defaults to the request path.
+func WriteProblem(w http.ResponseWriter, r *http.Request, p *Problem) {
+	if p.Instance == "" && r != nil {
+		p.Instance = r.URL.Path
+	}
+
+	w.Header().Set("Content-Type", ContentTypeProblem)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(p.Status)
+
+	json.NewEncoder(w).Encode(p) //nolint:errcheck
+}
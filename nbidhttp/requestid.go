@@ -0,0 +1,132 @@
+// MIT License
+//
+// Copyright (c) 2021 Iván Szkiba
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package nbidhttp
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/szkiba/nbid"
+)
+
+// HeaderRequestID is the default header carrying the request ID.
+const HeaderRequestID = "X-Request-ID"
+
+type requestIDKey struct{}
+
+// WithRequestID returns a copy of ctx carrying the request ID id.
+func WithRequestID(ctx context.Context, id nbid.NBID) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestID returns the request ID carried by ctx, or nbid.Nil if there is none.
+func RequestID(ctx context.Context) nbid.NBID {
+	id, _ := ctx.Value(requestIDKey{}).(nbid.NBID)
+
+	return id
+}
+
+// RequestIDOptions configures the RequestIDs middleware.
+type RequestIDOptions struct {
+	// Header is the request and response header carrying the ID.
+	// Defaults to HeaderRequestID.
+	Header string
+
+	// Trusted reports whether the request comes from a trusted upstream
+	// whose request ID header may be reused. If nil, incoming request IDs
+	// are never reused.
+	Trusted func(r *http.Request) bool
+}
+
+func (o RequestIDOptions) header() string {
+	if o.Header == "" {
+		return HeaderRequestID
+	}
+
+	return o.Header
+}
+
+// RequestIDs returns a middleware that tags every request with a request ID.
+//
+// The ID is taken from the request header if the request is trusted and the header
+// holds a valid NBID, otherwise a new one is generated using nbid.Random.
+// The ID is stored in the request context (see RequestID) and echoed in the
+// response header.
+func RequestIDs(opts RequestIDOptions) func(http.Handler) http.Handler {
+	header := opts.header()
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			id := nbid.Nil
+
+			if opts.Trusted != nil && opts.Trusted(r) {
+				if upstream, err := nbid.Parse(r.Header.Get(header)); err == nil {
+					id = upstream
+				}
+			}
+
+			if id.IsNil() {
+				id = nbid.Random()
+			}
+
+			w.Header().Set(header, id.String())
+
+			next.ServeHTTP(w, r.WithContext(WithRequestID(r.Context(), id)))
+		})
+	}
+}
+
+// Transport is an http.RoundTripper that injects the request ID found in the
+// context of outbound requests into the request header.
+// Requests without request ID or with the header already set are left intact.
+type Transport struct {
+	// Base is the underlying RoundTripper. Defaults to http.DefaultTransport.
+	Base http.RoundTripper
+
+	// Header is the header carrying the ID. Defaults to HeaderRequestID.
+	Header string
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *Transport) RoundTrip(r *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	header := t.Header
+	if header == "" {
+		header = HeaderRequestID
+	}
+
+	id := RequestID(r.Context())
+	if id.IsNil() || r.Header.Get(header) != "" {
+		return base.RoundTrip(r)
+	}
+
+	// RoundTrippers must not modify the original request.
+	r = r.Clone(r.Context())
+	r.Header.Set(header, id.String())
+
+	return base.RoundTrip(r)
+}
@@ -0,0 +1,128 @@
+// MIT License
+//
+// Copyright (c) 2021 Iván Szkiba
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package nbidhttp_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/szkiba/nbid"
+	"github.com/szkiba/nbid/nbidhttp"
+)
+
+func TestRequestIDContext(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, nbid.Nil, nbidhttp.RequestID(context.Background()))
+
+	id := nbid.Random()
+	ctx := nbidhttp.WithRequestID(context.Background(), id)
+
+	assert.Equal(t, id, nbidhttp.RequestID(ctx))
+}
+
+func TestRequestIDs(t *testing.T) {
+	t.Parallel()
+
+	trusted := func(r *http.Request) bool { return r.Header.Get("X-Trusted") != "" }
+
+	tests := []struct {
+		name     string
+		header   string
+		trusted  bool
+		wantSame bool
+	}{
+		{name: "generated"},
+		{name: "untrusted", header: sample},
+		{name: "trusted", header: sample, trusted: true, wantSame: true},
+		{name: "trusted_invalid", header: "XXX", trusted: true},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var got nbid.NBID
+
+			h := nbidhttp.RequestIDs(nbidhttp.RequestIDOptions{Trusted: trusted})(
+				http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					got = nbidhttp.RequestID(r.Context())
+				}))
+
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.header != "" {
+				r.Header.Set(nbidhttp.HeaderRequestID, tt.header)
+			}
+
+			if tt.trusted {
+				r.Header.Set("X-Trusted", "1")
+			}
+
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+
+			assert.False(t, got.IsNil())
+			assert.Equal(t, got.String(), w.Header().Get(nbidhttp.HeaderRequestID))
+
+			if tt.wantSame {
+				assert.Equal(t, tt.header, got.String())
+			} else {
+				assert.NotEqual(t, tt.header, got.String())
+			}
+		})
+	}
+}
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+func TestTransport(t *testing.T) {
+	t.Parallel()
+
+	var got string
+
+	tr := &nbidhttp.Transport{Base: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		got = r.Header.Get(nbidhttp.HeaderRequestID)
+
+		return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody}, nil
+	})}
+
+	id := nbid.Random()
+	r := httptest.NewRequest(http.MethodGet, "http://example.com/", nil)
+	r = r.WithContext(nbidhttp.WithRequestID(r.Context(), id))
+
+	_, err := tr.RoundTrip(r) //nolint:bodyclose
+	assert.Nil(t, err)
+	assert.Equal(t, id.String(), got)
+	assert.Empty(t, r.Header.Get(nbidhttp.HeaderRequestID))
+
+	_, err = tr.RoundTrip(httptest.NewRequest(http.MethodGet, "http://example.com/", nil)) //nolint:bodyclose
+	assert.Nil(t, err)
+	assert.Empty(t, got)
+}
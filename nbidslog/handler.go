@@ -0,0 +1,260 @@
+// MIT License
+//
+// Copyright (c) 2021 Iván Szkiba
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Package nbidslog contains a log/slog handler controlling how NBIDs are rendered.
+//
+// NBIDs implement slog.LogValuer, so they are logged in full by default.
+// For NBIDs derived from keyed or personal data it may be required to mask them
+// in logs, the Handler returned by NewHandler renders NBID attributes according
+// to its Mode before passing the record to the wrapped handler. Typed IDs
+// (nbid.ID[T]), valid nbid.NullNBID values and types implementing Identifier
+// are rendered the same way, even as elements of slices and arrays.
+//
+// Maps and struct fields are not inspected. Handlers render them with fmt, and
+// the verbose form of an NBID (as printed by slog.TextHandler) contains both its
+// string and hex form. Log such values as groups, or implement slog.LogValuer
+// on the containing type, to have their NBIDs rendered by Handler.
+package nbidslog
+
+import (
+	"context"
+	"log/slog"
+	"reflect"
+
+	"github.com/szkiba/nbid"
+)
+
+// Mode defines how NBIDs are rendered.
+type Mode int
+
+const (
+	// Full renders the whole string form.
+	Full Mode = iota
+	// Abbreviated renders the first Options.Chars characters of the string form followed by an ellipsis.
+	Abbreviated
+	// Redacted renders Options.Mask (or DefaultMask) instead of the NBID.
+	Redacted
+)
+
+const (
+	// DefaultChars is the default number of characters kept in Abbreviated mode.
+	DefaultChars = 6
+
+	// DefaultMask is the default replacement in Redacted mode.
+	DefaultMask = "[REDACTED]"
+
+	ellipsis = "…"
+)
+
+// Options for NewHandler.
+type Options struct {
+	// Mode defines how NBIDs are rendered.
+	Mode Mode
+
+	// Chars is the number of characters kept in Abbreviated mode. Defaults to DefaultChars.
+	Chars int
+
+	// Mask is the replacement in Redacted mode. Defaults to DefaultMask.
+	Mask string
+}
+
+// Render returns the string form of id according to the options.
+func (o Options) Render(id nbid.NBID) string {
+	switch o.Mode {
+	case Abbreviated:
+		n := o.Chars
+		if n <= 0 {
+			n = DefaultChars
+		}
+
+		s := id.String()
+		if n >= len(s) {
+			return s
+		}
+
+		return s[:n] + ellipsis
+
+	case Redacted:
+		if o.Mask == "" {
+			return DefaultMask
+		}
+
+		return o.Mask
+
+	default:
+		return id.String()
+	}
+}
+
+// Handler is a slog.Handler rendering NBID attributes before passing
+// records to the wrapped handler.
+type Handler struct {
+	next slog.Handler
+	opts Options
+}
+
+var _ slog.Handler = (*Handler)(nil)
+
+// NewHandler returns a new Handler wrapping next.
+func NewHandler(next slog.Handler, opts Options) *Handler {
+	return &Handler{next: next, opts: opts}
+}
+
+// Enabled implements slog.Handler.
+func (h *Handler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+// Handle implements slog.Handler.
+func (h *Handler) Handle(ctx context.Context, r slog.Record) error {
+	nr := slog.NewRecord(r.Time, r.Level, r.Message, r.PC)
+
+	r.Attrs(func(a slog.Attr) bool {
+		nr.AddAttrs(h.replace(a))
+
+		return true
+	})
+
+	return h.next.Handle(ctx, nr)
+}
+
+// WithAttrs implements slog.Handler.
+func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	replaced := make([]slog.Attr, len(attrs))
+	for i, a := range attrs {
+		replaced[i] = h.replace(a)
+	}
+
+	return &Handler{next: h.next.WithAttrs(replaced), opts: h.opts}
+}
+
+// WithGroup implements slog.Handler.
+func (h *Handler) WithGroup(name string) slog.Handler {
+	return &Handler{next: h.next.WithGroup(name), opts: h.opts}
+}
+
+func (h *Handler) replace(a slog.Attr) slog.Attr {
+	switch a.Value.Kind() {
+	case slog.KindGroup:
+		group := a.Value.Group()
+		replaced := make([]interface{}, len(group))
+
+		for i, ga := range group {
+			replaced[i] = h.replace(ga)
+		}
+
+		return slog.Group(a.Key, replaced...)
+
+	case slog.KindLogValuer, slog.KindAny:
+		if v, ok := h.render(a.Value.Any()); ok {
+			return slog.Any(a.Key, v)
+		}
+	}
+
+	return a
+}
+
+// render returns the rendered form of v if v is an NBID based value or a slice
+// or array containing NBID based values. Other elements are kept as is.
+func (h *Handler) render(v interface{}) (interface{}, bool) {
+	if id, ok := asNBID(v); ok {
+		return h.opts.Render(id), true
+	}
+
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return nil, false
+	}
+
+	switch rv.Type().Elem().Kind() {
+	case reflect.Array, reflect.Interface, reflect.Ptr, reflect.Slice, reflect.Struct:
+	default:
+		return nil, false
+	}
+
+	rendered := make([]interface{}, rv.Len())
+	found := false
+
+	for i := range rendered {
+		elem := rv.Index(i).Interface()
+
+		if r, ok := h.render(elem); ok {
+			elem = r
+			found = true
+		}
+
+		rendered[i] = elem
+	}
+
+	return rendered, found
+}
+
+// Identifier is implemented by NBID based types declared outside of package nbid
+// to be rendered by Handler.
+type Identifier interface {
+	NBID() nbid.NBID
+}
+
+var typeNBID = reflect.TypeOf(nbid.Nil)
+
+// asNBID returns the NBID of v if v is an NBID based value: an NBID, a typed ID
+// (like nbid.ID[T]), a valid nbid.NullNBID, an Identifier or a non-nil pointer to them.
+func asNBID(v interface{}) (nbid.NBID, bool) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return nbid.Nil, false
+		}
+
+		rv = rv.Elem()
+	}
+
+	switch v := v.(type) {
+	case Identifier:
+		return v.NBID(), true
+	case nbid.NullNBID:
+		return v.NBID, v.Valid
+	case *nbid.NullNBID:
+		return v.NBID, v.Valid
+	}
+
+	// types of package nbid with NBID as underlying type
+	if rv.Kind() != reflect.Array || rv.Type().PkgPath() != typeNBID.PkgPath() || !rv.Type().ConvertibleTo(typeNBID) {
+		return nbid.Nil, false
+	}
+
+	return rv.Convert(typeNBID).Interface().(nbid.NBID), true //nolint:forcetypeassert
+}
+
+// String returns the name of the mode.
+func (m Mode) String() string {
+	switch m {
+	case Full:
+		return "full"
+	case Abbreviated:
+		return "abbreviated"
+	case Redacted:
+		return "redacted"
+	default:
+		return "unknown"
+	}
+}
@@ -0,0 +1,195 @@
+// MIT License
+//
+// Copyright (c) 2021 Iván Szkiba
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package nbidslog_test
+
+import (
+	"bytes"
+	"fmt"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/szkiba/nbid"
+	"github.com/szkiba/nbid/nbidslog"
+)
+
+func TestHandler(t *testing.T) {
+	t.Parallel()
+
+	id := nbid.MustParse("QUKFNCO7QU098QEAJAUB021E9S")
+
+	tests := []struct {
+		name string
+		opts nbidslog.Options
+		out  string
+	}{
+		{
+			name: "full",
+			out:  "level=INFO msg=hello tenant=QUKFNCO7QU098QEAJAUB021E9S id=QUKFNCO7QU098QEAJAUB021E9S req.ptr=QUKFNCO7QU098QEAJAUB021E9S n=1\n",
+		},
+		{
+			name: "abbreviated",
+			opts: nbidslog.Options{Mode: nbidslog.Abbreviated},
+			out:  "level=INFO msg=hello tenant=QUKFNC… id=QUKFNC… req.ptr=QUKFNC… n=1\n",
+		},
+		{
+			name: "abbreviated_chars",
+			opts: nbidslog.Options{Mode: nbidslog.Abbreviated, Chars: 3},
+			out:  "level=INFO msg=hello tenant=QUK… id=QUK… req.ptr=QUK… n=1\n",
+		},
+		{
+			name: "redacted",
+			opts: nbidslog.Options{Mode: nbidslog.Redacted},
+			out:  "level=INFO msg=hello tenant=[REDACTED] id=[REDACTED] req.ptr=[REDACTED] n=1\n",
+		},
+		{
+			name: "redacted_mask",
+			opts: nbidslog.Options{Mode: nbidslog.Redacted, Mask: "***"},
+			out:  "level=INFO msg=hello tenant=*** id=*** req.ptr=*** n=1\n",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var buf bytes.Buffer
+
+			text := slog.NewTextHandler(&buf, &slog.HandlerOptions{
+				ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+					if a.Key == slog.TimeKey {
+						return slog.Attr{}
+					}
+
+					return a
+				},
+			})
+
+			logger := slog.New(nbidslog.NewHandler(text, tt.opts)).With("tenant", id)
+
+			logger.Info("hello", "id", id, slog.Group("req", "ptr", &id), "n", 1)
+
+			assert.Equal(t, tt.out, buf.String())
+		})
+	}
+}
+
+type user struct{}
+
+type accountID [16]byte
+
+func (id accountID) NBID() nbid.NBID {
+	return nbid.NBID(id)
+}
+
+func TestHandlerTyped(t *testing.T) {
+	t.Parallel()
+
+	id := nbid.MustParse("QUKFNCO7QU098QEAJAUB021E9S")
+	typed := nbid.ID[user](id)
+
+	var buf bytes.Buffer
+
+	text := slog.NewTextHandler(&buf, &slog.HandlerOptions{
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+
+			return a
+		},
+	})
+
+	logger := slog.New(nbidslog.NewHandler(text, nbidslog.Options{Mode: nbidslog.Redacted}))
+
+	logger.Info("hello",
+		"typed", typed,
+		"typed_ptr", &typed,
+		"null", nbid.NullNBID{NBID: id, Valid: true},
+		"null_ptr", &nbid.NullNBID{NBID: id, Valid: true},
+		"account", accountID(id),
+		"invalid", nbid.NullNBID{},
+		"prefixed", nbid.Prefixed{Kind: "user", ID: id},
+	)
+
+	assert.Equal(t,
+		"level=INFO msg=hello typed=[REDACTED] typed_ptr=[REDACTED] null=[REDACTED] null_ptr=[REDACTED]"+
+			" account=[REDACTED] invalid=\"\" prefixed=[REDACTED]\n",
+		buf.String())
+	assert.NotContains(t, buf.String(), id.String())
+}
+
+func TestHandlerCollections(t *testing.T) {
+	t.Parallel()
+
+	id := nbid.MustParse("QUKFNCO7QU098QEAJAUB021E9S")
+	typed := nbid.ID[user](id)
+
+	var buf bytes.Buffer
+
+	text := slog.NewTextHandler(&buf, &slog.HandlerOptions{
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+
+			return a
+		},
+	})
+
+	logger := slog.New(nbidslog.NewHandler(text, nbidslog.Options{Mode: nbidslog.Redacted}))
+
+	logger.Info("hello",
+		"slice", []nbid.NBID{id, id},
+		"array", [1]nbid.ID[user]{typed},
+		"ptrs", []*nbid.NBID{&id, nil},
+		"mixed", []interface{}{id, "x", 1},
+		"nested", [][]nbid.NBID{{id}},
+		"bytes", []byte("ab"),
+		"strings", []string{"a"},
+	)
+
+	assert.Equal(t,
+		"level=INFO msg=hello slice=\"[[REDACTED] [REDACTED]]\" array=[[REDACTED]] ptrs=\"[[REDACTED] <nil>]\""+
+			" mixed=\"[[REDACTED] x 1]\" nested=[[[REDACTED]]] bytes=\"ab\" strings=[a]\n",
+		buf.String())
+	assert.NotContains(t, buf.String(), id.String())
+
+	// maps and struct fields are not inspected
+	buf.Reset()
+
+	logger.Info("hello", "map", map[string]nbid.NBID{"a": id}, "struct", struct{ ID nbid.NBID }{id})
+
+	assert.Contains(t, buf.String(), "map=\"map[a:"+id.String())
+	assert.Contains(t, buf.String(), "struct=\"{ID:"+id.String())
+	assert.Contains(t, buf.String(), fmt.Sprintf("hex: %x", id[:]))
+}
+
+func TestMode(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "full", nbidslog.Full.String())
+	assert.Equal(t, "abbreviated", nbidslog.Abbreviated.String())
+	assert.Equal(t, "redacted", nbidslog.Redacted.String())
+	assert.Equal(t, "unknown", nbidslog.Mode(42).String())
+}
@@ -0,0 +1,195 @@
+// MIT License
+//
+// Copyright (c) 2021 Iván Szkiba
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package nbid
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/unicode/norm"
+)
+
+// ErrInvalidNormalizer is returned by ParseNormalizer for unknown options.
+var ErrInvalidNormalizer = errors.New("nbid: invalid normalizer")
+
+// Form is a Unicode normalization form.
+type Form int
+
+const (
+	// NoForm leaves the Unicode representation as is.
+	NoForm Form = iota
+	// NFC is the canonical composition form, e.g. "Café" becomes "Café".
+	NFC
+	// NFKC is the compatibility composition form, it also folds compatibility
+	// variants, e.g. "\uff21" (fullwidth A) becomes "A" and "\ufb01" (fi ligature) becomes "fi".
+	NFKC
+)
+
+// Normalizer defines how names are normalized before hashing, so different
+// representations of the same name result in the same NBID.
+//
+// The steps are applied in the following order: Unicode normalization (Form),
+// case folding (FoldCase), whitespace trimming (TrimSpace) and whitespace
+// collapsing (CollapseSpace).
+//
+// Stability: for given options the normalized form of a name (and so its NBID)
+// will not change in future versions of this package, with one exception:
+// code points unassigned in the Unicode version of golang.org/x/text in use
+// may normalize differently once they get assigned.
+type Normalizer struct {
+	// Form is the Unicode normalization form.
+	Form Form
+
+	// FoldCase applies Unicode full case folding as implemented by golang.org/x/text/cases.Fold,
+	// e.g. "Alice@Example.COM" becomes "alice@example.com" and "Straße" becomes "strasse".
+	FoldCase bool
+
+	// TrimSpace removes leading and trailing Unicode white space.
+	TrimSpace bool
+
+	// CollapseSpace replaces runs of Unicode white space with a single ASCII space.
+	CollapseSpace bool
+}
+
+// Normalize returns the normalized form of name.
+func (n Normalizer) Normalize(name string) string {
+	name = n.form(name)
+
+	if n.FoldCase {
+		name = n.form(cases.Fold().String(name))
+	}
+
+	if n.TrimSpace {
+		name = strings.TrimSpace(name)
+	}
+
+	if n.CollapseSpace {
+		name = collapseSpace(name)
+	}
+
+	return name
+}
+
+func (n Normalizer) form(s string) string {
+	switch n.Form {
+	case NFC:
+		return norm.NFC.String(s)
+	case NFKC:
+		return norm.NFKC.String(s)
+	default:
+		return s
+	}
+}
+
+func collapseSpace(s string) string {
+	var b strings.Builder
+
+	b.Grow(len(s))
+
+	space := false
+
+	for _, r := range s {
+		if unicode.IsSpace(r) {
+			space = true
+
+			continue
+		}
+
+		if space {
+			b.WriteByte(' ')
+
+			space = false
+		}
+
+		b.WriteRune(r)
+	}
+
+	if space {
+		b.WriteByte(' ')
+	}
+
+	return b.String()
+}
+
+// String returns the options as a comma separated list accepted by ParseNormalizer.
+func (n Normalizer) String() string {
+	var opts []string
+
+	switch n.Form {
+	case NFC:
+		opts = append(opts, "nfc")
+	case NFKC:
+		opts = append(opts, "nfkc")
+	}
+
+	if n.FoldCase {
+		opts = append(opts, "fold")
+	}
+
+	if n.TrimSpace {
+		opts = append(opts, "trim")
+	}
+
+	if n.CollapseSpace {
+		opts = append(opts, "collapse")
+	}
+
+	return strings.Join(opts, ",")
+}
+
+// ParseNormalizer parses a comma separated list of normalizer options:
+// "nfc", "nfkc", "fold", "trim" and "collapse".
+func ParseNormalizer(spec string) (Normalizer, error) {
+	var n Normalizer
+
+	for _, opt := range strings.Split(spec, ",") {
+		switch strings.ToLower(strings.TrimSpace(opt)) {
+		case "":
+		case "nfc":
+			n.Form = NFC
+		case "nfkc":
+			n.Form = NFKC
+		case "fold":
+			n.FoldCase = true
+		case "trim":
+			n.TrimSpace = true
+		case "collapse":
+			n.CollapseSpace = true
+		default:
+			return n, fmt.Errorf("%w: unknown option %q", ErrInvalidNormalizer, opt)
+		}
+	}
+
+	return n, nil
+}
+
+// NewNormalized returns a new NBID derived from the SHA256 hash of name
+// normalized by opts. It is the same as calling:
+//
+//  New([]byte(opts.Normalize(name)))
+func NewNormalized(name string, opts Normalizer) NBID {
+	return New([]byte(opts.Normalize(name)))
+}
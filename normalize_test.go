@@ -0,0 +1,105 @@
+// MIT License
+//
+// Copyright (c) 2021 Iván Szkiba
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package nbid_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/szkiba/nbid"
+)
+
+func TestNormalize(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		opts nbid.Normalizer
+		in   string
+		out  string
+	}{
+		{name: "none", in: " Cafe\u0301 ", out: " Cafe\u0301 "},
+		{name: "nfc", opts: nbid.Normalizer{Form: nbid.NFC}, in: "Cafe\u0301", out: "Caf\u00e9"},
+		{name: "nfc_compat", opts: nbid.Normalizer{Form: nbid.NFC}, in: "\ufb01", out: "\ufb01"},
+		{name: "nfkc", opts: nbid.Normalizer{Form: nbid.NFKC}, in: "\ufb01\uff21", out: "fiA"},
+		{name: "fold", opts: nbid.Normalizer{FoldCase: true}, in: "Alice@Example.COM", out: "alice@example.com"},
+		{name: "fold_special", opts: nbid.Normalizer{FoldCase: true}, in: "\u017f\u212a", out: "sk"},
+		{name: "fold_full", opts: nbid.Normalizer{FoldCase: true}, in: "Stra\u00dfe", out: "strasse"},
+		{name: "fold_cherokee", opts: nbid.Normalizer{FoldCase: true}, in: "\uab70\u13f8", out: "\u13a0\u13f0"},
+		{name: "fold_nfc", opts: nbid.Normalizer{Form: nbid.NFC, FoldCase: true}, in: "CAFE\u0301", out: "caf\u00e9"},
+		{name: "trim", opts: nbid.Normalizer{TrimSpace: true}, in: "\t a  b \u00a0\n", out: "a  b"},
+		{name: "collapse", opts: nbid.Normalizer{CollapseSpace: true}, in: " a \t\n b\u3000c ", out: " a b c "},
+		{
+			name: "all",
+			opts: nbid.Normalizer{Form: nbid.NFKC, FoldCase: true, TrimSpace: true, CollapseSpace: true},
+			in:   "  Jose\u0301   GARC\u0130A  ",
+			out:  "jos\u00e9 garci\u0307a",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.out, tt.opts.Normalize(tt.in))
+			assert.Equal(t, nbid.New([]byte(tt.out)), nbid.NewNormalized(tt.in, tt.opts))
+		})
+	}
+}
+
+func TestNormalizeStability(t *testing.T) {
+	t.Parallel()
+
+	opts := nbid.Normalizer{Form: nbid.NFC, FoldCase: true, TrimSpace: true}
+
+	// NFD (macOS) and NFC (Linux) input, different case
+	assert.Equal(t, nbid.NewNormalized("Cafe\u0301", opts), nbid.NewNormalized(" caf\u00e9", opts))
+	assert.NotEqual(t, nbid.New([]byte("Cafe\u0301")), nbid.New([]byte("Caf\u00e9")))
+
+	// the NBID of a normalized name must never change
+	assert.Equal(t, "0N3E13OTJVDFK0OKFV5OV0NH4G",
+		nbid.NewNormalized("  THE QUICK BROWN FOX JUMPS OVER THE LAZY DOG ", nbid.Normalizer{
+			Form: nbid.NFKC, FoldCase: true, TrimSpace: true, CollapseSpace: true,
+		}).String())
+}
+
+func TestParseNormalizer(t *testing.T) {
+	t.Parallel()
+
+	n, err := nbid.ParseNormalizer("NFKC, fold,trim,collapse")
+	assert.Nil(t, err)
+	assert.Equal(t, nbid.Normalizer{Form: nbid.NFKC, FoldCase: true, TrimSpace: true, CollapseSpace: true}, n)
+	assert.Equal(t, "nfkc,fold,trim,collapse", n.String())
+
+	n, err = nbid.ParseNormalizer(n.String())
+	assert.Nil(t, err)
+	assert.Equal(t, "nfkc,fold,trim,collapse", n.String())
+
+	n, err = nbid.ParseNormalizer("")
+	assert.Nil(t, err)
+	assert.Equal(t, nbid.Normalizer{}, n)
+
+	_, err = nbid.ParseNormalizer("nfd")
+	assert.True(t, errors.Is(err, nbid.ErrInvalidNormalizer))
+}
@@ -0,0 +1,118 @@
+// MIT License
+//
+// Copyright (c) 2021 Iván Szkiba
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package nbid
+
+import (
+	"database/sql/driver"
+)
+
+// NullNBID represents an NBID that may be null.
+// NullNBID implements the sql.Scanner interface so it can be used as a scan destination,
+// similar to sql.NullString.
+type NullNBID struct {
+	NBID  NBID
+	Valid bool // Valid is true if NBID is not NULL
+}
+
+// Scan implements sql.Scanner.
+func (n *NullNBID) Scan(src interface{}) error {
+	if src == nil {
+		n.NBID, n.Valid = Nil, false
+
+		return nil
+	}
+
+	if err := n.NBID.Scan(src); err != nil {
+		n.Valid = false
+
+		return err
+	}
+
+	n.Valid = true
+
+	return nil
+}
+
+// Value implements sql.Valuer.
+func (n NullNBID) Value() (driver.Value, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+
+	return n.NBID.Value()
+}
+
+// MarshalText implements encoding/text TextMarshaler interface.
+// An invalid NullNBID is marshalled as empty text.
+func (n NullNBID) MarshalText() ([]byte, error) {
+	if !n.Valid {
+		return []byte{}, nil
+	}
+
+	return n.NBID.MarshalText()
+}
+
+// UnmarshalText implements encoding/text TextUnmarshaler interface.
+// Empty text results in an invalid NullNBID.
+func (n *NullNBID) UnmarshalText(text []byte) error {
+	if len(text) == 0 {
+		n.NBID, n.Valid = Nil, false
+
+		return nil
+	}
+
+	if err := n.NBID.UnmarshalText(text); err != nil {
+		return err
+	}
+
+	n.Valid = true
+
+	return nil
+}
+
+// MarshalJSON implements encoding/json Marshaler interface.
+// An invalid NullNBID is marshalled as null.
+func (n NullNBID) MarshalJSON() ([]byte, error) {
+	if !n.Valid {
+		return []byte("null"), nil
+	}
+
+	text, err := n.NBID.MarshalText()
+
+	return []byte(`"` + string(text) + `"`), err
+}
+
+// UnmarshalJSON implements encoding/json Unmarshaler interface.
+func (n *NullNBID) UnmarshalJSON(b []byte) error {
+	if string(b) == "null" {
+		n.NBID, n.Valid = Nil, false
+
+		return nil
+	}
+
+	if len(b) < 2 || b[0] != '"' || b[len(b)-1] != '"' {
+		return &ParseError{Input: string(b), Reason: "not a JSON string"}
+	}
+
+	return n.UnmarshalText(b[1 : len(b)-1])
+}
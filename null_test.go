@@ -0,0 +1,92 @@
+// MIT License
+//
+// Copyright (c) 2021 Iván Szkiba
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package nbid_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/szkiba/nbid"
+)
+
+func TestNullNBID(t *testing.T) {
+	t.Parallel()
+
+	type x struct {
+		ID nbid.NullNBID `json:"id"`
+	}
+
+	id := nbid.MustParse("QUKFNCO7QU098QEAJAUB021E9S")
+
+	tests := []struct {
+		name string
+		json string
+		want nbid.NullNBID
+	}{
+		{name: "normal", json: `{"id":"QUKFNCO7QU098QEAJAUB021E9S"}`, want: nbid.NullNBID{NBID: id, Valid: true}},
+		{name: "null", json: `{"id":null}`, want: nbid.NullNBID{}},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			data := x{}
+			assert.Nil(t, json.Unmarshal([]byte(tt.json), &data))
+			assert.Equal(t, tt.want, data.ID)
+
+			b, err := json.Marshal(data)
+			assert.Nil(t, err)
+			assert.Equal(t, tt.json, string(b))
+		})
+	}
+
+	data := x{}
+	assert.Error(t, json.Unmarshal([]byte(`{"id":42}`), &data))
+}
+
+func TestNullNBIDSQL(t *testing.T) {
+	t.Parallel()
+
+	id := nbid.MustParse("QUKFNCO7QU098QEAJAUB021E9S")
+
+	var n nbid.NullNBID
+
+	assert.Nil(t, n.Scan(id.String()))
+	assert.Equal(t, nbid.NullNBID{NBID: id, Valid: true}, n)
+
+	val, err := n.Value()
+	assert.Nil(t, err)
+	assert.Equal(t, id.String(), val)
+
+	assert.Nil(t, n.Scan(nil))
+	assert.False(t, n.Valid)
+
+	val, err = n.Value()
+	assert.Nil(t, err)
+	assert.Nil(t, val)
+
+	assert.Error(t, n.Scan("XXX"))
+	assert.False(t, n.Valid)
+}
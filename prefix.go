@@ -0,0 +1,273 @@
+// MIT License
+//
+// Copyright (c) 2021 Iván Szkiba
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package nbid
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// PrefixSeparator separates the prefix and the NBID in prefixed IDs.
+const PrefixSeparator = "_"
+
+var (
+	// ErrInvalidPrefix is returned when registering a malformed or already registered prefix or kind.
+	ErrInvalidPrefix = errors.New("nbid: invalid prefix")
+
+	// ErrUnknownPrefix is returned when a prefix or kind is not registered.
+	ErrUnknownPrefix = errors.New("nbid: unknown prefix")
+
+	// ErrPrefixMismatch is returned when a prefixed ID belongs to another kind than expected.
+	ErrPrefixMismatch = errors.New("nbid: prefix mismatch")
+
+	// DefaultPrefixes is the registry used by Prefixed and the package level prefix functions.
+	DefaultPrefixes = NewPrefixRegistry()
+)
+
+// PrefixRegistry maps object kinds to ID prefixes, e.g. "user" to "usr".
+// Prefixes are lowercase letters and digits starting with a letter.
+// A PrefixRegistry is safe for concurrent use.
+type PrefixRegistry struct {
+	mu       sync.RWMutex
+	byKind   map[string]string
+	byPrefix map[string]string
+}
+
+// NewPrefixRegistry returns a new, empty PrefixRegistry.
+func NewPrefixRegistry() *PrefixRegistry {
+	return &PrefixRegistry{
+		byKind:   make(map[string]string),
+		byPrefix: make(map[string]string),
+	}
+}
+
+// Register registers prefix for kind.
+// Both kind and prefix can be registered only once.
+func (r *PrefixRegistry) Register(kind string, prefix string) error {
+	if kind == "" || !validPrefix(prefix) {
+		return fmt.Errorf("%w: %q for kind %q", ErrInvalidPrefix, prefix, kind)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if p, ok := r.byKind[kind]; ok {
+		return fmt.Errorf("%w: kind %q already registered with prefix %q", ErrInvalidPrefix, kind, p)
+	}
+
+	if k, ok := r.byPrefix[prefix]; ok {
+		return fmt.Errorf("%w: %q already registered for kind %q", ErrInvalidPrefix, prefix, k)
+	}
+
+	r.byKind[kind] = prefix
+	r.byPrefix[prefix] = kind
+
+	return nil
+}
+
+// MustRegister is like Register but panics on error.
+func (r *PrefixRegistry) MustRegister(kind string, prefix string) {
+	if err := r.Register(kind, prefix); err != nil {
+		panic(err)
+	}
+}
+
+// Prefix returns the prefix registered for kind.
+func (r *PrefixRegistry) Prefix(kind string) (string, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	p, ok := r.byKind[kind]
+
+	return p, ok
+}
+
+// Format returns the prefixed string form of id for kind, e.g. "usr_QUKFNCO7QU098QEAJAUB021E9S".
+func (r *PrefixRegistry) Format(kind string, id NBID) (string, error) {
+	prefix, ok := r.Prefix(kind)
+	if !ok {
+		return "", fmt.Errorf("%w: kind %q", ErrUnknownPrefix, kind)
+	}
+
+	return prefix + PrefixSeparator + id.String(), nil
+}
+
+// Parse decodes the prefixed ID s of the expected kind.
+// Returns an error wrapping ErrPrefixMismatch if s has the prefix of another kind.
+func (r *PrefixRegistry) Parse(kind string, s string) (NBID, error) {
+	got, id, err := r.ParseAny(s)
+	if err != nil {
+		return Nil, err
+	}
+
+	if got != kind {
+		return Nil, fmt.Errorf("%w: %q is %s, expected %s", ErrPrefixMismatch, s, got, kind)
+	}
+
+	return id, nil
+}
+
+// ParseAny decodes the prefixed ID s and returns its kind and NBID.
+func (r *PrefixRegistry) ParseAny(s string) (string, NBID, error) {
+	idx := strings.LastIndex(s, PrefixSeparator)
+	if idx < 0 {
+		return "", Nil, &ParseError{Input: s, Reason: "missing prefix"}
+	}
+
+	prefix := s[:idx]
+
+	r.mu.RLock()
+	kind, ok := r.byPrefix[prefix]
+	r.mu.RUnlock()
+
+	if !ok {
+		return "", Nil, fmt.Errorf("%w: %q", ErrUnknownPrefix, prefix)
+	}
+
+	var id NBID
+
+	if err := id.UnmarshalText([]byte(s[idx+len(PrefixSeparator):])); err != nil {
+		return "", Nil, err
+	}
+
+	return kind, id, nil
+}
+
+func validPrefix(p string) bool {
+	if p == "" || p[0] < 'a' || p[0] > 'z' {
+		return false
+	}
+
+	for i := 1; i < len(p); i++ {
+		if (p[i] < 'a' || p[i] > 'z') && (p[i] < '0' || p[i] > '9') {
+			return false
+		}
+	}
+
+	return true
+}
+
+// RegisterPrefix registers prefix for kind in DefaultPrefixes.
+func RegisterPrefix(kind string, prefix string) error {
+	return DefaultPrefixes.Register(kind, prefix)
+}
+
+// ParseAny decodes the prefixed ID s using DefaultPrefixes and returns its kind and NBID.
+func ParseAny(s string) (string, NBID, error) {
+	return DefaultPrefixes.ParseAny(s)
+}
+
+// Prefixed is an NBID of a given kind represented with the prefix registered
+// for the kind in DefaultPrefixes, e.g. "usr_QUKFNCO7QU098QEAJAUB021E9S".
+//
+// When unmarshalling into a Prefixed with Kind set, the prefix must belong to
+// that kind. With empty Kind, any registered prefix is accepted and Kind is set.
+type Prefixed struct {
+	Kind string
+	ID   NBID
+}
+
+// String returns the prefixed string form, or the plain NBID string form if Kind is not registered.
+func (p Prefixed) String() string {
+	s, err := DefaultPrefixes.Format(p.Kind, p.ID)
+	if err != nil {
+		return p.ID.String()
+	}
+
+	return s
+}
+
+// NBID returns the NBID without the prefix.
+func (p Prefixed) NBID() NBID {
+	return p.ID
+}
+
+// MarshalText implements encoding/text TextMarshaler interface.
+func (p Prefixed) MarshalText() ([]byte, error) {
+	s, err := DefaultPrefixes.Format(p.Kind, p.ID)
+
+	return []byte(s), err
+}
+
+// UnmarshalText implements encoding/text TextUnmarshaler interface.
+func (p *Prefixed) UnmarshalText(text []byte) error {
+	kind, id, err := DefaultPrefixes.ParseAny(string(text))
+	if err != nil {
+		return err
+	}
+
+	if p.Kind != "" && p.Kind != kind {
+		return fmt.Errorf("%w: %q is %s, expected %s", ErrPrefixMismatch, text, kind, p.Kind)
+	}
+
+	p.Kind, p.ID = kind, id
+
+	return nil
+}
+
+// MarshalJSON implements encoding/json Marshaler interface.
+func (p Prefixed) MarshalJSON() ([]byte, error) {
+	text, err := p.MarshalText()
+	if err != nil {
+		return nil, err
+	}
+
+	return []byte(`"` + string(text) + `"`), nil
+}
+
+// UnmarshalJSON implements encoding/json Unmarshaler interface.
+func (p *Prefixed) UnmarshalJSON(b []byte) error {
+	if len(b) < 2 || b[0] != '"' || b[len(b)-1] != '"' {
+		return &ParseError{Input: string(b), Reason: "not a JSON string"}
+	}
+
+	return p.UnmarshalText(b[1 : len(b)-1])
+}
+
+// Scan implements sql.Scanner, string and []byte sources are supported.
+// A nil source (SQL NULL) leaves p unchanged.
+func (p *Prefixed) Scan(src interface{}) error {
+	switch src := src.(type) {
+	case nil:
+		return nil
+	case string:
+		return p.UnmarshalText([]byte(src))
+	case []byte:
+		return p.UnmarshalText(src)
+	default:
+		return fmt.Errorf("%w: unable to scan type %T", ErrInvalidID, src)
+	}
+}
+
+// Value implements sql.Valuer.
+func (p Prefixed) Value() (driver.Value, error) {
+	text, err := p.MarshalText()
+	if err != nil {
+		return nil, err
+	}
+
+	return string(text), nil
+}
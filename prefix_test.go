@@ -0,0 +1,130 @@
+// MIT License
+//
+// Copyright (c) 2021 Iván Szkiba
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package nbid_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/szkiba/nbid"
+)
+
+func init() { //nolint:gochecknoinits
+	nbid.DefaultPrefixes.MustRegister("user", "usr")
+	nbid.DefaultPrefixes.MustRegister("order", "ord")
+}
+
+func TestPrefixRegistry(t *testing.T) {
+	t.Parallel()
+
+	r := nbid.NewPrefixRegistry()
+
+	assert.Nil(t, r.Register("user", "usr"))
+	assert.Nil(t, r.Register("invoice", "in2"))
+
+	for _, tt := range [][2]string{{"user", "u"}, {"customer", "usr"}, {"x", "Usr"}, {"x", "u_r"}, {"x", "1u"}, {"x", ""}, {"", "x"}} {
+		assert.True(t, errors.Is(r.Register(tt[0], tt[1]), nbid.ErrInvalidPrefix), tt)
+	}
+
+	id := nbid.MustParse("QUKFNCO7QU098QEAJAUB021E9S")
+
+	s, err := r.Format("user", id)
+	assert.Nil(t, err)
+	assert.Equal(t, "usr_QUKFNCO7QU098QEAJAUB021E9S", s)
+
+	_, err = r.Format("customer", id)
+	assert.True(t, errors.Is(err, nbid.ErrUnknownPrefix))
+
+	got, err := r.Parse("user", s)
+	assert.Nil(t, err)
+	assert.Equal(t, id, got)
+
+	_, err = r.Parse("invoice", s)
+	assert.True(t, errors.Is(err, nbid.ErrPrefixMismatch))
+
+	kind, got, err := r.ParseAny("in2_QUKFNCO7QU098QEAJAUB021E9S")
+	assert.Nil(t, err)
+	assert.Equal(t, "invoice", kind)
+	assert.Equal(t, id, got)
+
+	_, _, err = r.ParseAny("QUKFNCO7QU098QEAJAUB021E9S")
+	assert.True(t, errors.Is(err, nbid.ErrInvalidID))
+
+	_, _, err = r.ParseAny("cus_QUKFNCO7QU098QEAJAUB021E9S")
+	assert.True(t, errors.Is(err, nbid.ErrUnknownPrefix))
+
+	_, _, err = r.ParseAny("usr_XXX")
+
+	var perr *nbid.ParseError
+
+	assert.True(t, errors.As(err, &perr))
+}
+
+func TestPrefixed(t *testing.T) {
+	t.Parallel()
+
+	type x struct {
+		Owner nbid.Prefixed `json:"owner"`
+	}
+
+	id := nbid.MustParse("QUKFNCO7QU098QEAJAUB021E9S")
+	in := x{Owner: nbid.Prefixed{Kind: "user", ID: id}}
+
+	assert.Equal(t, "usr_QUKFNCO7QU098QEAJAUB021E9S", in.Owner.String())
+
+	b, err := json.Marshal(in)
+	assert.Nil(t, err)
+	assert.Equal(t, `{"owner":"usr_QUKFNCO7QU098QEAJAUB021E9S"}`, string(b))
+
+	var out x
+
+	assert.Nil(t, json.Unmarshal(b, &out))
+	assert.Equal(t, in, out)
+
+	expect := x{Owner: nbid.Prefixed{Kind: "order"}}
+	assert.True(t, errors.Is(json.Unmarshal(b, &expect), nbid.ErrPrefixMismatch))
+
+	_, err = json.Marshal(x{Owner: nbid.Prefixed{Kind: "unknown", ID: id}})
+	assert.Error(t, err)
+
+	kind, got, err := nbid.ParseAny("ord_QUKFNCO7QU098QEAJAUB021E9S")
+	assert.Nil(t, err)
+	assert.Equal(t, "order", kind)
+	assert.Equal(t, id, got)
+
+	// SQL
+	val, err := in.Owner.Value()
+	assert.Nil(t, err)
+	assert.Equal(t, "usr_QUKFNCO7QU098QEAJAUB021E9S", val)
+
+	scanned := nbid.Prefixed{Kind: "user"}
+	assert.Nil(t, scanned.Scan([]byte("usr_QUKFNCO7QU098QEAJAUB021E9S")))
+	assert.Equal(t, in.Owner, scanned)
+	assert.Nil(t, scanned.Scan(nil))
+	assert.Equal(t, in.Owner, scanned)
+	assert.Error(t, scanned.Scan(42))
+
+	assert.Equal(t, id, in.Owner.NBID())
+}
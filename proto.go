@@ -0,0 +1,86 @@
+// MIT License
+//
+// Copyright (c) 2021 Iván Szkiba
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package nbid
+
+import (
+	"fmt"
+)
+
+// The methods below make NBID usable as github.com/gogo/protobuf customtype
+// of bytes fields. They operate on the 16 byte binary representation, the
+// same as MarshalBinary and UnmarshalBinary. Equal and Compare are also
+// part of the method set expected by gogoproto.
+
+// Marshal returns the binary representation of NBID.
+func (id NBID) Marshal() ([]byte, error) {
+	b := make([]byte, rawLen)
+
+	copy(b, id[:])
+
+	return b, nil
+}
+
+// MarshalTo writes the binary representation of NBID into data.
+// Returns an error if data is shorter than 16 bytes.
+func (id NBID) MarshalTo(data []byte) (n int, err error) {
+	if len(data) < rawLen {
+		return 0, fmt.Errorf("%w: buffer too small (%d bytes)", ErrInvalidID, len(data))
+	}
+
+	return copy(data, id[:]), nil
+}
+
+// Unmarshal decodes the binary representation of NBID.
+// Empty data results in Nil NBID, otherwise data must be 16 bytes long.
+func (id *NBID) Unmarshal(data []byte) error {
+	if len(data) == 0 {
+		*id = Nil
+
+		return nil
+	}
+
+	return id.UnmarshalBinary(data)
+}
+
+// Size returns the length of the binary representation of NBID (always 16).
+func (id NBID) Size() int {
+	return rawLen
+}
+
+// Randy is the subset of the random source interface used by gogoproto
+// generated populate functions.
+type Randy interface {
+	Intn(n int) int
+}
+
+// NewPopulatedNBID returns a new NBID filled with random bytes from r.
+// It is used by gogoproto generated test code.
+func NewPopulatedNBID(r Randy) *NBID {
+	var id NBID
+
+	for i := range id {
+		id[i] = byte(r.Intn(256)) //nolint:gomnd
+	}
+
+	return &id
+}
@@ -0,0 +1,138 @@
+// MIT License
+//
+// Copyright (c) 2021 Iván Szkiba
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package nbid_test
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/szkiba/nbid"
+)
+
+// message is a hand-written encoder/decoder for the following protobuf message:
+//
+//	message Message {
+//	  bytes id = 1 [(gogoproto.customtype) = "github.com/szkiba/nbid.NBID", (gogoproto.nullable) = false];
+//	  string name = 2;
+//	}
+type message struct {
+	ID   nbid.NBID
+	Name string
+}
+
+const (
+	wireBytes = 2 // length-delimited wire type
+)
+
+func (m *message) marshal() []byte {
+	buf := make([]byte, 0, 2+m.ID.Size()+2+len(m.Name))
+
+	buf = binary.AppendUvarint(buf, 1<<3|wireBytes)
+	buf = binary.AppendUvarint(buf, uint64(m.ID.Size()))
+	start := len(buf)
+	buf = buf[:start+m.ID.Size()]
+
+	if _, err := m.ID.MarshalTo(buf[start:]); err != nil {
+		panic(err)
+	}
+
+	buf = binary.AppendUvarint(buf, 2<<3|wireBytes)
+	buf = binary.AppendUvarint(buf, uint64(len(m.Name)))
+
+	return append(buf, m.Name...)
+}
+
+func (m *message) unmarshal(data []byte) error {
+	for len(data) > 0 {
+		key, n := binary.Uvarint(data)
+		data = data[n:]
+
+		size, n := binary.Uvarint(data)
+		data = data[n:]
+
+		field := data[:size]
+		data = data[size:]
+
+		switch key >> 3 {
+		case 1:
+			if err := m.ID.Unmarshal(field); err != nil {
+				return err
+			}
+		case 2:
+			m.Name = string(field)
+		}
+	}
+
+	return nil
+}
+
+func TestProtoWireFormat(t *testing.T) {
+	t.Parallel()
+
+	in := message{ID: nbid.MustParse("QUKFNCO7QU098QEAJAUB021E9S"), Name: "fox"}
+
+	data := in.marshal()
+
+	assert.Equal(t, "0a10d7a8fbb307d7809469ca9abcb0082e4f1203666f78", hex.EncodeToString(data))
+
+	var out message
+
+	assert.Nil(t, out.unmarshal(data))
+	assert.Equal(t, in, out)
+	assert.True(t, in.ID.Equal(out.ID))
+	assert.Equal(t, 0, in.ID.Compare(out.ID))
+}
+
+func TestProto(t *testing.T) {
+	t.Parallel()
+
+	id := nbid.MustParse("QUKFNCO7QU098QEAJAUB021E9S")
+
+	b, err := id.Marshal()
+	assert.Nil(t, err)
+	assert.Equal(t, id.Bytes(), b)
+
+	b[0] = 0
+	assert.NotEqual(t, id.Bytes(), b, "Marshal must return a copy")
+
+	_, err = id.MarshalTo(make([]byte, 8))
+	assert.Error(t, err)
+
+	var got nbid.NBID
+
+	assert.Nil(t, got.Unmarshal(id.Bytes()))
+	assert.Equal(t, id, got)
+
+	assert.Nil(t, got.Unmarshal(nil))
+	assert.True(t, got.IsNil())
+
+	assert.Error(t, got.Unmarshal([]byte{1, 2, 3}))
+
+	assert.Equal(t, 16, id.Size())
+
+	p := nbid.NewPopulatedNBID(rand.New(rand.NewSource(1))) //nolint:gosec
+	assert.False(t, p.IsNil())
+}
@@ -0,0 +1,72 @@
+// MIT License
+//
+// Copyright (c) 2021 Iván Szkiba
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package nbid
+
+// Pattern is the regular expression matching the string form of NBIDs.
+const Pattern = "^[0-9A-V]{26}$"
+
+// JSONSchemaDialect is the JSON Schema dialect of the schemas returned by JSONSchema.
+// It is also the default dialect of OpenAPI 3.1.
+const JSONSchemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+const schemaDescription = "Name Based ID, 16 bytes encoded as 26 characters of base32 hex (RFC 4648) without padding"
+
+// Schema is a JSON Schema describing a JSON value.
+// Only the keywords required to describe NBID are included.
+type Schema struct {
+	Schema      string   `json:"$schema,omitempty"`
+	ID          string   `json:"$id,omitempty"`
+	Title       string   `json:"title,omitempty"`
+	Description string   `json:"description,omitempty"`
+	Type        string   `json:"type"`
+	Pattern     string   `json:"pattern,omitempty"`
+	MinLength   int      `json:"minLength,omitempty"`
+	MaxLength   int      `json:"maxLength,omitempty"`
+	Examples    []string `json:"examples,omitempty"`
+}
+
+// JSONSchema returns the JSON Schema of the JSON representation of NBID.
+// The nil NBID, which is marshalled to JSON null, is not covered by the schema.
+func (NBID) JSONSchema() *Schema {
+	return &Schema{
+		Title:       "NBID",
+		Description: schemaDescription,
+		Type:        "string",
+		Pattern:     Pattern,
+		MinLength:   encodedLen,
+		MaxLength:   encodedLen,
+		Examples:    []string{"QUKFNCO7QU098QEAJAUB021E9S"},
+	}
+}
+
+// OpenAPIComponent returns an OpenAPI 3.1 document fragment declaring NBID
+// as the "NBID" component schema, to be referenced as "#/components/schemas/NBID".
+func OpenAPIComponent() map[string]interface{} {
+	return map[string]interface{}{
+		"components": map[string]interface{}{
+			"schemas": map[string]interface{}{
+				"NBID": Nil.JSONSchema(),
+			},
+		},
+	}
+}
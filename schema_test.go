@@ -0,0 +1,83 @@
+// MIT License
+//
+// Copyright (c) 2021 Iván Szkiba
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package nbid_test
+
+import (
+	"encoding/json"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/szkiba/nbid"
+)
+
+func TestJSONSchema(t *testing.T) {
+	t.Parallel()
+
+	b, err := json.Marshal(nbid.Nil.JSONSchema())
+	assert.Nil(t, err)
+
+	var s struct {
+		Type      string `json:"type"`
+		Pattern   string `json:"pattern"`
+		MinLength int    `json:"minLength"`
+		MaxLength int    `json:"maxLength"`
+	}
+
+	assert.Nil(t, json.Unmarshal(b, &s))
+	assert.Equal(t, "string", s.Type)
+	assert.Equal(t, 26, s.MinLength)
+	assert.Equal(t, 26, s.MaxLength)
+
+	re := regexp.MustCompile(s.Pattern)
+
+	for i := 0; i < 100; i++ {
+		id := nbid.Random()
+		assert.True(t, re.MatchString(id.String()), id.String())
+	}
+
+	for _, ex := range nbid.Nil.JSONSchema().Examples {
+		assert.True(t, re.MatchString(ex))
+	}
+
+	for _, in := range []string{"", "small", "XXXXXXXXXXXXXXXXXXXXXXXXXX", "qukfnco7qu098qeajaub021e9s", "QUKFNCO7QU098QEAJAUB021E9SA"} {
+		assert.False(t, re.MatchString(in), in)
+	}
+}
+
+func TestOpenAPIComponent(t *testing.T) {
+	t.Parallel()
+
+	b, err := json.Marshal(nbid.OpenAPIComponent())
+	assert.Nil(t, err)
+
+	var doc struct {
+		Components struct {
+			Schemas map[string]json.RawMessage `json:"schemas"`
+		} `json:"components"`
+	}
+
+	assert.Nil(t, json.Unmarshal(b, &doc))
+	assert.Contains(t, doc.Components.Schemas, "NBID")
+	assert.Contains(t, string(doc.Components.Schemas["NBID"]), `"pattern":"^[0-9A-V]{26}$"`)
+}
@@ -0,0 +1,166 @@
+// MIT License
+//
+// Copyright (c) 2021 Iván Szkiba
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package nbid
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+const (
+	setHashLanes  = 16
+	setHashBlocks = setHashLanes * 8 / sha256.Size
+	setHashSize   = setHashLanes*8 + 8
+	setHashDomain = "github.com/szkiba/nbid#set"
+)
+
+// ErrInvalidSetHash is returned when decoding a malformed binary SetHash.
+var ErrInvalidSetHash = errors.New("nbid: invalid set hash")
+
+// SetHash is an order independent, incrementally updatable fingerprint of a multiset of NBIDs.
+//
+// SetHash is an additive (lattice) homomorphic multiset hash: every member is
+// expanded to 1024 bits using SHA256 and added lane-wise to the state as sixteen
+// 64 bit integers modulo 2^64. Adding and removing a member are O(1) and
+// commutative, so the same multiset always gives the same Sum regardless of the
+// order of updates. Adding a member twice is different from adding it once.
+//
+// The zero value is the hash of the empty set. SetHash values are comparable.
+type SetHash struct {
+	lanes [setHashLanes]uint64
+	count uint64
+}
+
+// NewSetHash returns the SetHash of ids.
+func NewSetHash(ids ...NBID) *SetHash {
+	s := new(SetHash)
+	s.Add(ids...)
+
+	return s
+}
+
+// Add adds ids to the set.
+func (s *SetHash) Add(ids ...NBID) {
+	for _, id := range ids {
+		e := expandSetMember(id)
+
+		for i := range s.lanes {
+			s.lanes[i] += e[i]
+		}
+
+		s.count++
+	}
+}
+
+// Remove removes ids from the set.
+// Removing a member that wasn't added leaves the set in a state that is undone by adding the member.
+func (s *SetHash) Remove(ids ...NBID) {
+	for _, id := range ids {
+		e := expandSetMember(id)
+
+		for i := range s.lanes {
+			s.lanes[i] -= e[i]
+		}
+
+		s.count--
+	}
+}
+
+// Union adds all members of other to the set.
+func (s *SetHash) Union(other *SetHash) {
+	for i := range s.lanes {
+		s.lanes[i] += other.lanes[i]
+	}
+
+	s.count += other.count
+}
+
+// Len returns the number of members of the set (counting multiplicity).
+func (s *SetHash) Len() int {
+	return int(s.count)
+}
+
+// Reset resets the set to the empty set.
+func (s *SetHash) Reset() {
+	*s = SetHash{}
+}
+
+// Sum returns the NBID fingerprint of the set.
+func (s *SetHash) Sum() NBID {
+	data, _ := s.MarshalBinary()
+
+	return New(data)
+}
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface.
+// The state is encoded as the lanes and the member count as big endian 64 bit integers.
+func (s *SetHash) MarshalBinary() ([]byte, error) {
+	data := make([]byte, 0, setHashSize)
+
+	for _, lane := range s.lanes {
+		data = binary.BigEndian.AppendUint64(data, lane)
+	}
+
+	return binary.BigEndian.AppendUint64(data, s.count), nil
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface.
+func (s *SetHash) UnmarshalBinary(data []byte) error {
+	if len(data) != setHashSize {
+		return fmt.Errorf("%w: invalid length %d, expected %d", ErrInvalidSetHash, len(data), setHashSize)
+	}
+
+	for i := range s.lanes {
+		s.lanes[i] = binary.BigEndian.Uint64(data[i*8:])
+	}
+
+	s.count = binary.BigEndian.Uint64(data[setHashLanes*8:])
+
+	return nil
+}
+
+func expandSetMember(id NBID) [setHashLanes]uint64 {
+	var (
+		e   [setHashLanes]uint64
+		buf [sha256.Size]byte
+	)
+
+	h := sha256.New()
+
+	for block := 0; block < setHashBlocks; block++ {
+		h.Reset()
+		h.Write([]byte(setHashDomain))
+		h.Write([]byte{byte(block)})
+		h.Write(id[:])
+
+		sum := h.Sum(buf[:0])
+
+		for i := 0; i < sha256.Size/8; i++ {
+			e[block*sha256.Size/8+i] = binary.BigEndian.Uint64(sum[i*8:])
+		}
+	}
+
+	return e
+}
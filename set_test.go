@@ -0,0 +1,128 @@
+// MIT License
+//
+// Copyright (c) 2021 Iván Szkiba
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package nbid_test
+
+import (
+	"errors"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/szkiba/nbid"
+)
+
+func TestSetHash(t *testing.T) {
+	t.Parallel()
+
+	a := nbid.New([]byte("a"))
+	b := nbid.New([]byte("b"))
+
+	var empty nbid.SetHash
+
+	assert.Equal(t, "MS3I85A5KD32CMLB3VTJ5VR4MK", empty.Sum().String())
+	assert.Equal(t, "I0FJ8ACSOV1FI6U0T5RVN58MQ8", nbid.NewSetHash(a, b).Sum().String())
+	assert.Equal(t, 2, nbid.NewSetHash(a, b).Len())
+
+	assert.NotEqual(t, nbid.NewSetHash(a).Sum(), nbid.NewSetHash(a, a).Sum(), "multiplicity counts")
+	assert.NotEqual(t, nbid.NewSetHash(a).Sum(), nbid.NewSetHash(b).Sum())
+
+	s := nbid.NewSetHash(a, b)
+	s.Reset()
+	assert.Equal(t, empty, *s)
+}
+
+func TestSetHashOrderIndependent(t *testing.T) {
+	t.Parallel()
+
+	ids := make([]nbid.NBID, 100)
+	for i := range ids {
+		ids[i] = nbid.Random()
+	}
+
+	want := nbid.NewSetHash(ids...).Sum()
+
+	rnd := rand.New(rand.NewSource(42)) //nolint:gosec
+
+	for i := 0; i < 10; i++ {
+		rnd.Shuffle(len(ids), func(i, j int) { ids[i], ids[j] = ids[j], ids[i] })
+
+		assert.Equal(t, want, nbid.NewSetHash(ids...).Sum())
+	}
+}
+
+func TestSetHashIncremental(t *testing.T) {
+	t.Parallel()
+
+	ids := make([]nbid.NBID, 20)
+	for i := range ids {
+		ids[i] = nbid.Random()
+	}
+
+	s := nbid.NewSetHash(ids...)
+
+	// remove odd members one by one
+	for i := 1; i < len(ids); i += 2 {
+		s.Remove(ids[i])
+	}
+
+	even := nbid.NewSetHash()
+	for i := 0; i < len(ids); i += 2 {
+		even.Add(ids[i])
+	}
+
+	assert.Equal(t, even.Sum(), s.Sum())
+	assert.Equal(t, *even, *s)
+	assert.Equal(t, 10, s.Len())
+
+	odd := nbid.NewSetHash()
+	for i := 1; i < len(ids); i += 2 {
+		odd.Add(ids[i])
+	}
+
+	s.Union(odd)
+	assert.Equal(t, nbid.NewSetHash(ids...).Sum(), s.Sum())
+
+	s.Remove(ids...)
+	assert.Equal(t, nbid.SetHash{}, *s)
+
+	// removal before addition
+	s.Remove(ids[0])
+	s.Add(ids[0])
+	assert.Equal(t, nbid.SetHash{}, *s)
+}
+
+func TestSetHashBinary(t *testing.T) {
+	t.Parallel()
+
+	s := nbid.NewSetHash(nbid.New([]byte("a")), nbid.New([]byte("b")))
+
+	data, err := s.MarshalBinary()
+	assert.Nil(t, err)
+
+	var decoded nbid.SetHash
+
+	assert.Nil(t, decoded.UnmarshalBinary(data))
+	assert.Equal(t, *s, decoded)
+
+	assert.True(t, errors.Is(decoded.UnmarshalBinary(data[1:]), nbid.ErrInvalidSetHash))
+}
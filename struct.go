@@ -0,0 +1,173 @@
+// MIT License
+//
+// Copyright (c) 2021 Iván Szkiba
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package nbid
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// ErrInvalidStruct is returned by FromStruct for values without key fields or with malformed tags.
+var ErrInvalidStruct = errors.New("nbid: invalid struct")
+
+// FromStruct returns an NBID derived from the key fields of the struct (or pointer to struct) v.
+//
+// Key fields are marked with the `nbid:"key"` struct tag. They are encoded in
+// declaration order, or in the order given by the order option (`nbid:"key,order=2"`,
+// fields without order come last). Each field is encoded injectively with
+// its type and length (see NewFields), field names are not part of the encoding,
+// so renaming a field does not change the ID.
+//
+// The namespace of the ID can be given with an `nbid:"ns=NAME"` tag on any field
+// (usually the blank field), the namespace is the NBID of NAME. Otherwise the
+// namespace bound to the struct type by BindNamespace or Namespacer is used,
+// if any. The ID is derived using NewNamespaced with the namespace, or New without it.
+//
+//	type Order struct {
+//		_      struct{} `nbid:"ns=order"`
+//		Tenant string   `nbid:"key"`
+//		Number int      `nbid:"key"`
+//		Note   string
+//	}
+//
+// Reflection metadata is cached per type.
+func FromStruct(v interface{}) (NBID, error) {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return Nil, fmt.Errorf("%w: nil pointer", ErrInvalidStruct)
+		}
+
+		rv = rv.Elem()
+	}
+
+	if rv.Kind() != reflect.Struct {
+		return Nil, fmt.Errorf("%w: %T is not a struct", ErrInvalidStruct, v)
+	}
+
+	info, err := structInfoOf(rv.Type())
+	if err != nil {
+		return Nil, err
+	}
+
+	var data []byte
+
+	for _, f := range info.fields {
+		if data, err = appendField(data, rv.FieldByIndex(f.index)); err != nil {
+			return Nil, fmt.Errorf("%w: field %s", err, f.name)
+		}
+	}
+
+	if info.hasNS {
+		return NewNamespaced(info.ns, data), nil
+	}
+
+	return New(data), nil
+}
+
+type keyField struct {
+	name  string
+	index []int
+	order int
+}
+
+type structInfo struct {
+	fields []keyField
+	ns     NBID
+	hasNS  bool
+}
+
+var structInfos sync.Map // map[reflect.Type]*structInfo
+
+func structInfoOf(t reflect.Type) (*structInfo, error) {
+	if info, ok := structInfos.Load(t); ok {
+		return info.(*structInfo), nil //nolint:forcetypeassert
+	}
+
+	info := &structInfo{}
+
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+
+		tag, ok := sf.Tag.Lookup("nbid")
+		if !ok {
+			continue
+		}
+
+		key := false
+		order := int(^uint(0) >> 1)
+
+		for _, opt := range strings.Split(tag, ",") {
+			name, value, _ := strings.Cut(strings.TrimSpace(opt), "=")
+
+			switch name {
+			case "key":
+				key = true
+			case "order":
+				n, err := strconv.Atoi(value)
+				if err != nil {
+					return nil, fmt.Errorf("%w: %s.%s: invalid order %q", ErrInvalidStruct, t, sf.Name, value)
+				}
+
+				order = n
+			case "ns":
+				if value == "" {
+					return nil, fmt.Errorf("%w: %s.%s: empty namespace", ErrInvalidStruct, t, sf.Name)
+				}
+
+				info.ns, info.hasNS = New([]byte(value)), true
+			default:
+				return nil, fmt.Errorf("%w: %s.%s: unknown tag option %q", ErrInvalidStruct, t, sf.Name, opt)
+			}
+		}
+
+		if key && !sf.IsExported() {
+			return nil, fmt.Errorf("%w: %s.%s: unexported key field", ErrInvalidStruct, t, sf.Name)
+		}
+
+		if key {
+			info.fields = append(info.fields, keyField{name: sf.Name, index: sf.Index, order: order})
+		}
+	}
+
+	if len(info.fields) == 0 {
+		return nil, fmt.Errorf("%w: %s has no key fields", ErrInvalidStruct, t)
+	}
+
+	sort.SliceStable(info.fields, func(i, j int) bool {
+		return info.fields[i].order < info.fields[j].order
+	})
+
+	if !info.hasNS {
+		info.ns, info.hasNS = namespaceOfType(t)
+	}
+
+	actual, _ := structInfos.LoadOrStore(t, info)
+
+	return actual.(*structInfo), nil //nolint:forcetypeassert
+}
@@ -0,0 +1,182 @@
+// MIT License
+//
+// Copyright (c) 2021 Iván Szkiba
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package nbid_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/szkiba/nbid"
+)
+
+type orderKey struct {
+	_      struct{} `nbid:"ns=order"`
+	Tenant string   `nbid:"key"`
+	Number int      `nbid:"key"`
+	Note   string
+}
+
+type orderKeyReordered struct {
+	Number int `nbid:"key,order=2"`
+	Note   string
+	Tenant string   `nbid:"key,order=1"`
+	_      struct{} `nbid:"ns=order"`
+}
+
+type shipment struct {
+	Order   nbid.NBID         `nbid:"key"`
+	Created time.Time         `nbid:"key"`
+	Express *bool             `nbid:"key"`
+	Owner   nbid.ID[user]     `nbid:"key"`
+	Data    []byte            `nbid:"key"`
+	Count   uint16            `nbid:"key"`
+	Labels  map[string]string // not a key
+}
+
+type userKey struct {
+	Email string `nbid:"key"`
+}
+
+func (userKey) Namespace() nbid.NBID {
+	return nbid.New([]byte("user"))
+}
+
+func TestFromStruct(t *testing.T) {
+	t.Parallel()
+
+	a, err := nbid.FromStruct(orderKey{Tenant: "acme", Number: 42, Note: "x"})
+	assert.Nil(t, err)
+
+	b, err := nbid.FromStruct(&orderKey{Tenant: "acme", Number: 42, Note: "y"})
+	assert.Nil(t, err)
+
+	c, err := nbid.FromStruct(orderKeyReordered{Tenant: "acme", Number: 42})
+	assert.Nil(t, err)
+
+	assert.Equal(t, a, b, "non-key fields are ignored")
+	assert.Equal(t, a, c, "field names and declaration order do not matter with explicit order")
+
+	d, err := nbid.FromStruct(orderKey{Tenant: "acme", Number: 43})
+	assert.Nil(t, err)
+	assert.NotEqual(t, a, d)
+
+	// stable test vector
+	assert.Equal(t, "4RTBPDGFQO2AAJOBPH3A54NLDG", a.String())
+}
+
+func TestFromStructTypes(t *testing.T) {
+	t.Parallel()
+
+	express := true
+	s := shipment{
+		Order:   nbid.MustParse("QUKFNCO7QU098QEAJAUB021E9S"),
+		Created: time.Date(2021, 3, 14, 12, 0, 0, 0, time.UTC),
+		Express: &express,
+		Owner:   nbid.MustParseID[user]("QUKFNCO7QU098QEAJAUB021E9S"),
+		Data:    []byte{1, 2, 3},
+		Count:   7,
+	}
+
+	a, err := nbid.FromStruct(s)
+	assert.Nil(t, err)
+
+	// the same instant in another location
+	s.Created = s.Created.In(time.FixedZone("CET", 3600))
+
+	b, err := nbid.FromStruct(s)
+	assert.Nil(t, err)
+	assert.Equal(t, a, b)
+
+	s.Express = nil
+
+	_, err = nbid.FromStruct(s)
+	assert.True(t, errors.Is(err, nbid.ErrUnsupportedField))
+}
+
+func TestFromStructNamedBytes(t *testing.T) {
+	t.Parallel()
+
+	type octet uint8
+
+	type key struct {
+		Data []octet `nbid:"key"`
+	}
+
+	a, err := nbid.FromStruct(key{Data: []octet{1, 2}})
+	assert.Nil(t, err)
+
+	b, err := nbid.FromStruct(struct {
+		Data []byte `nbid:"key"`
+	}{Data: []byte{1, 2}})
+	assert.Nil(t, err)
+
+	assert.Equal(t, a, b)
+}
+
+func TestFromStructNamespacer(t *testing.T) {
+	t.Parallel()
+
+	a, err := nbid.FromStruct(userKey{Email: "alice@example.com"})
+	assert.Nil(t, err)
+
+	b, err := nbid.FromStruct(struct {
+		Email string `nbid:"key"`
+	}{Email: "alice@example.com"})
+	assert.Nil(t, err)
+
+	assert.NotEqual(t, a, b)
+}
+
+func TestFromStructErrors(t *testing.T) {
+	t.Parallel()
+
+	type noKeys struct {
+		Name string
+	}
+
+	type badOrder struct {
+		Name string `nbid:"key,order=x"`
+	}
+
+	type badOption struct {
+		Name string `nbid:"primary"`
+	}
+
+	type badType struct {
+		Score float64 `nbid:"key"`
+	}
+
+	type unexported struct {
+		name string `nbid:"key"`
+	}
+
+	for _, v := range []interface{}{42, (*orderKey)(nil), noKeys{}, badOrder{}, badOption{}, unexported{}} {
+		_, err := nbid.FromStruct(v)
+		assert.True(t, errors.Is(err, nbid.ErrInvalidStruct), "%T", v)
+	}
+
+	_, err := nbid.FromStruct(badType{})
+	assert.True(t, errors.Is(err, nbid.ErrUnsupportedField))
+}
@@ -0,0 +1,111 @@
+// MIT License
+//
+// Copyright (c) 2021 Iván Szkiba
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package nbid
+
+import (
+	"fmt"
+)
+
+// DefaultShortLen is the default length of the short form used by the nbidShort template function.
+const DefaultShortLen = 8
+
+// FuncMap returns template functions for minting and formatting NBIDs.
+// The result can be passed to Funcs of both text/template and html/template.
+//
+//	nbid NAME            NBID of NAME (see New)
+//	nbidRandom           random NBID (see Random)
+//	nbidNS NS NAME       NBID of NAME in namespace NS (see NewNamespaced)
+//	nbidParse S          NBID parsed from S (see Parse)
+//	nbidShort ID [N]     first N (default 8) characters of the string form
+//	nbidHex ID           lowercase hex form
+//	nbidUUID ID          hex form in UUID layout (8-4-4-4-12)
+//
+// Function arguments denoting an NBID accept NBID values or their string form.
+// Invalid arguments result in template execution errors.
+func FuncMap() map[string]interface{} {
+	return map[string]interface{}{
+		"nbid": func(name string) NBID {
+			return New([]byte(name))
+		},
+		"nbidRandom": Random,
+		"nbidNS": func(ns interface{}, name string) (NBID, error) {
+			id, err := toNBID(ns)
+			if err != nil {
+				return Nil, err
+			}
+
+			return NewNamespaced(id, []byte(name)), nil
+		},
+		"nbidParse": Parse,
+		"nbidShort": func(v interface{}, n ...int) (string, error) {
+			id, err := toNBID(v)
+			if err != nil {
+				return "", err
+			}
+
+			l := DefaultShortLen
+			if len(n) > 0 {
+				l = n[0]
+			}
+
+			if l < 0 || l > encodedLen {
+				return "", fmt.Errorf("%w: short length %d out of range", ErrInvalidID, l)
+			}
+
+			return id.String()[:l], nil
+		},
+		"nbidHex": func(v interface{}) (string, error) {
+			id, err := toNBID(v)
+			if err != nil {
+				return "", err
+			}
+
+			return fmt.Sprintf("%x", id[:]), nil
+		},
+		"nbidUUID": func(v interface{}) (string, error) {
+			id, err := toNBID(v)
+			if err != nil {
+				return "", err
+			}
+
+			return fmt.Sprintf("%x-%x-%x-%x-%x", id[0:4], id[4:6], id[6:8], id[8:10], id[10:]), nil
+		},
+	}
+}
+
+func toNBID(v interface{}) (NBID, error) {
+	switch v := v.(type) {
+	case NBID:
+		return v, nil
+	case *NBID:
+		if v == nil {
+			return Nil, nil
+		}
+
+		return *v, nil
+	case string:
+		return Parse(v)
+	default:
+		return Nil, &ParseError{Input: fmt.Sprint(v), Reason: fmt.Sprintf("unsupported type %T", v)}
+	}
+}
@@ -0,0 +1,92 @@
+// MIT License
+//
+// Copyright (c) 2021 Iván Szkiba
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package nbid_test
+
+import (
+	"bytes"
+	htmltemplate "html/template"
+	"strings"
+	"testing"
+	"text/template"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/szkiba/nbid"
+)
+
+func TestFuncMap(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		text    string
+		out     string
+		wantErr bool
+	}{
+		{name: "nbid", text: `{{nbid "The quick brown fox jumps over the lazy dog"}}`, out: "QUKFNCO7QU098QEAJAUB021E9S"},
+		{
+			name: "nbidNS",
+			text: `{{nbidNS (nbid "ns") "x"}} {{nbidNS "QUKFNCO7QU098QEAJAUB021E9S" "x"}}`,
+			out: nbid.NewNamespaced(nbid.New([]byte("ns")), []byte("x")).String() + " " +
+				nbid.NewNamespaced(nbid.MustParse("QUKFNCO7QU098QEAJAUB021E9S"), []byte("x")).String(),
+		},
+		{name: "nbidParse", text: `{{(nbidParse "QUKFNCO7QU098QEAJAUB021E9S").TraceID}}`, out: "d7a8fbb307d7809469ca9abcb0082e4f"},
+		{name: "nbidShort", text: `{{nbidShort "QUKFNCO7QU098QEAJAUB021E9S"}} {{nbidShort "QUKFNCO7QU098QEAJAUB021E9S" 4}}`, out: "QUKFNCO7 QUKF"},
+		{name: "nbidHex", text: `{{nbidHex "QUKFNCO7QU098QEAJAUB021E9S"}}`, out: "d7a8fbb307d7809469ca9abcb0082e4f"},
+		{name: "nbidUUID", text: `{{nbid "The quick brown fox jumps over the lazy dog" | nbidUUID}}`, out: "d7a8fbb3-07d7-8094-69ca-9abcb0082e4f"},
+		{name: "nbidRandom", text: `{{len (nbidRandom).String}}`, out: "26"},
+		{name: "parse_error", text: `{{nbidParse "XXX"}}`, wantErr: true},
+		{name: "short_error", text: `{{nbidShort "QUKFNCO7QU098QEAJAUB021E9S" 27}}`, wantErr: true},
+		{name: "type_error", text: `{{nbidHex 42}}`, wantErr: true},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			tmpl := template.Must(template.New(tt.name).Funcs(nbid.FuncMap()).Parse(tt.text))
+
+			var buf bytes.Buffer
+
+			err := tmpl.Execute(&buf, nil)
+			if tt.wantErr {
+				assert.Error(t, err)
+
+				return
+			}
+
+			assert.Nil(t, err)
+			assert.Equal(t, tt.out, buf.String())
+		})
+	}
+}
+
+func TestFuncMapHTML(t *testing.T) {
+	t.Parallel()
+
+	tmpl := htmltemplate.Must(htmltemplate.New("html").Funcs(nbid.FuncMap()).Parse(`<a id="{{nbid .}}">{{nbidShort (nbid .)}}</a>`))
+
+	var buf strings.Builder
+
+	assert.Nil(t, tmpl.Execute(&buf, "The quick brown fox jumps over the lazy dog"))
+	assert.Equal(t, `<a id="QUKFNCO7QU098QEAJAUB021E9S">QUKFNCO7</a>`, buf.String())
+}
@@ -0,0 +1,180 @@
+// MIT License
+//
+// Copyright (c) 2021 Iván Szkiba
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package nbid
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrInvalidTraceparent is returned when a traceparent header value cannot be parsed.
+var ErrInvalidTraceparent = errors.New("nbid: invalid traceparent")
+
+const (
+	traceIDLen     = 32 // hex encoded trace ID len
+	spanIDLen      = 16 // hex encoded span ID len
+	traceparentLen = 55 // version 00 traceparent len
+	flagSampled    = 0x01
+)
+
+// SpanID is an 8 byte W3C Trace Context parent (span) ID.
+type SpanID [8]byte
+
+// String returns the lowercase hex form of SpanID.
+func (s SpanID) String() string {
+	return hex.EncodeToString(s[:])
+}
+
+// IsZero returns true if all bytes of SpanID are zero (an invalid span ID).
+func (s SpanID) IsZero() bool {
+	return s == SpanID{}
+}
+
+// TraceID returns the W3C Trace Context trace ID form of NBID (32 lowercase hex characters).
+// Both are 16 bytes long, so the binary representation is the same.
+func (id NBID) TraceID() string {
+	return hex.EncodeToString(id[:])
+}
+
+// SpanID returns a span ID derived from NBID.
+// The first 8 bytes of the SHA256 hash of the binary NBID are used,
+// so the same NBID always results in the same span ID.
+func (id NBID) SpanID() SpanID {
+	sum := sha256.Sum256(id[:])
+
+	var s SpanID
+
+	copy(s[:], sum[:])
+
+	if s.IsZero() {
+		s[len(s)-1] = 1
+	}
+
+	return s
+}
+
+// FromTraceID decodes a W3C Trace Context trace ID (32 lowercase hex characters) into an NBID.
+// Returns a *ParseError if s is malformed or all zero (an invalid trace ID).
+func FromTraceID(s string) (NBID, error) {
+	var id NBID
+
+	if err := decodeLowerHex(id[:], s, traceIDLen); err != "" {
+		return Nil, &ParseError{Input: s, Reason: err}
+	}
+
+	if id.IsNil() {
+		return Nil, &ParseError{Input: s, Reason: "all zero trace ID"}
+	}
+
+	return id, nil
+}
+
+// Traceparent returns a version 00 W3C Trace Context traceparent header value
+// with id as trace ID.
+func Traceparent(id NBID, spanID SpanID, sampled bool) string {
+	flags := "00"
+	if sampled {
+		flags = "01"
+	}
+
+	return "00-" + id.TraceID() + "-" + spanID.String() + "-" + flags
+}
+
+// ParseTraceparent parses a W3C Trace Context traceparent header value.
+// Version ff and all zero trace or span IDs are rejected. Values with a future
+// version are accepted if they start with a valid version 00 prefix.
+func ParseTraceparent(s string) (id NBID, spanID SpanID, sampled bool, err error) {
+	invalid := func(reason string) (NBID, SpanID, bool, error) {
+		return Nil, SpanID{}, false, fmt.Errorf("%w %q: %s", ErrInvalidTraceparent, s, reason)
+	}
+
+	if len(s) < traceparentLen {
+		return invalid("too short")
+	}
+
+	var version [1]byte
+	if reason := decodeLowerHex(version[:], s[:2], 2); reason != "" {
+		return invalid("version: " + reason)
+	}
+
+	switch {
+	case version[0] == 0xff:
+		return invalid("forbidden version ff")
+	case version[0] == 0 && len(s) != traceparentLen:
+		return invalid("invalid length for version 00")
+	case len(s) > traceparentLen && s[traceparentLen] != '-':
+		return invalid("missing delimiter after flags")
+	}
+
+	parts := strings.SplitN(s[:traceparentLen], "-", 4)
+	if len(parts) != 4 || len(parts[1]) != traceIDLen || len(parts[2]) != spanIDLen {
+		return invalid("malformed fields")
+	}
+
+	if reason := decodeLowerHex(id[:], parts[1], traceIDLen); reason != "" {
+		return invalid("trace ID: " + reason)
+	}
+
+	if reason := decodeLowerHex(spanID[:], parts[2], spanIDLen); reason != "" {
+		return invalid("parent ID: " + reason)
+	}
+
+	var flags [1]byte
+	if reason := decodeLowerHex(flags[:], parts[3], 2); reason != "" {
+		return invalid("flags: " + reason)
+	}
+
+	if id.IsNil() {
+		return invalid("all zero trace ID")
+	}
+
+	if spanID.IsZero() {
+		return invalid("all zero parent ID")
+	}
+
+	return id, spanID, flags[0]&flagSampled != 0, nil
+}
+
+// decodeLowerHex decodes exactly n lowercase hex characters of s into dst.
+// Returns the reason of the failure or empty string on success.
+func decodeLowerHex(dst []byte, s string, n int) string {
+	if len(s) != n {
+		return fmt.Sprintf("invalid length %d, expected %d", len(s), n)
+	}
+
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return fmt.Sprintf("illegal character %q at offset %d", c, i)
+		}
+	}
+
+	if _, err := hex.Decode(dst, []byte(s)); err != nil {
+		return err.Error()
+	}
+
+	return ""
+}
@@ -0,0 +1,112 @@
+// MIT License
+//
+// Copyright (c) 2021 Iván Szkiba
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package nbid_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/szkiba/nbid"
+)
+
+func TestTraceID(t *testing.T) {
+	t.Parallel()
+
+	id := nbid.New([]byte("The quick brown fox jumps over the lazy dog"))
+
+	assert.Equal(t, "d7a8fbb307d7809469ca9abcb0082e4f", id.TraceID())
+
+	got, err := nbid.FromTraceID(id.TraceID())
+	assert.Nil(t, err)
+	assert.Equal(t, id, got)
+
+	for _, in := range []string{
+		"00000000000000000000000000000000",
+		"D7A8FBB307D7809469CA9ABCB0082E4F",
+		"d7a8fbb307d7809469ca9abcb0082e4",
+		"d7a8fbb307d7809469ca9abcb0082e4g",
+	} {
+		_, err := nbid.FromTraceID(in)
+		assert.True(t, errors.Is(err, nbid.ErrInvalidID), in)
+	}
+}
+
+func TestSpanID(t *testing.T) {
+	t.Parallel()
+
+	id := nbid.New([]byte("The quick brown fox jumps over the lazy dog"))
+
+	assert.Equal(t, id.SpanID(), id.SpanID())
+	assert.False(t, id.SpanID().IsZero())
+	assert.Len(t, id.SpanID().String(), 16)
+	assert.NotEqual(t, id.SpanID(), nbid.Random().SpanID())
+}
+
+func TestTraceparent(t *testing.T) {
+	t.Parallel()
+
+	id := nbid.MustParse("QUKFNCO7QU098QEAJAUB021E9S")
+	span := nbid.SpanID{0x00, 0xf0, 0x67, 0xaa, 0x0b, 0xa9, 0x02, 0xb7}
+
+	assert.Equal(t, "00-d7a8fbb307d7809469ca9abcb0082e4f-00f067aa0ba902b7-01", nbid.Traceparent(id, span, true))
+	assert.Equal(t, "00-d7a8fbb307d7809469ca9abcb0082e4f-00f067aa0ba902b7-00", nbid.Traceparent(id, span, false))
+
+	tests := []struct {
+		name    string
+		in      string
+		sampled bool
+		wantErr bool
+	}{
+		{name: "sampled", in: "00-d7a8fbb307d7809469ca9abcb0082e4f-00f067aa0ba902b7-01", sampled: true},
+		{name: "not_sampled", in: "00-d7a8fbb307d7809469ca9abcb0082e4f-00f067aa0ba902b7-00"},
+		{name: "future_version", in: "01-d7a8fbb307d7809469ca9abcb0082e4f-00f067aa0ba902b7-09-xyz", sampled: true},
+		{name: "version_ff", in: "ff-d7a8fbb307d7809469ca9abcb0082e4f-00f067aa0ba902b7-01", wantErr: true},
+		{name: "version_00_long", in: "00-d7a8fbb307d7809469ca9abcb0082e4f-00f067aa0ba902b7-01-xyz", wantErr: true},
+		{name: "future_no_delimiter", in: "01-d7a8fbb307d7809469ca9abcb0082e4f-00f067aa0ba902b7-01xyz", wantErr: true},
+		{name: "uppercase", in: "00-D7A8FBB307D7809469CA9ABCB0082E4F-00f067aa0ba902b7-01", wantErr: true},
+		{name: "zero_trace", in: "00-00000000000000000000000000000000-00f067aa0ba902b7-01", wantErr: true},
+		{name: "zero_span", in: "00-d7a8fbb307d7809469ca9abcb0082e4f-0000000000000000-01", wantErr: true},
+		{name: "short", in: "00-d7a8fbb307d7809469ca9abcb0082e4f-00f067aa0ba902b7", wantErr: true},
+		{name: "delimiter", in: "00_d7a8fbb307d7809469ca9abcb0082e4f-00f067aa0ba902b7-01", wantErr: true},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			gotID, gotSpan, sampled, err := nbid.ParseTraceparent(tt.in)
+
+			if tt.wantErr {
+				assert.True(t, errors.Is(err, nbid.ErrInvalidTraceparent))
+
+				return
+			}
+
+			assert.Nil(t, err)
+			assert.Equal(t, id, gotID)
+			assert.Equal(t, span, gotSpan)
+			assert.Equal(t, tt.sampled, sampled)
+		})
+	}
+}
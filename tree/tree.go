@@ -0,0 +1,329 @@
+// MIT License
+//
+// Copyright (c) 2021 Iván Szkiba
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Package tree computes Merkle-style NBIDs of file trees.
+//
+// The ID of a regular file is the NBID of its content (the same as
+// nbid.New of the whole content, computed by streaming). The ID of a symbolic
+// link is the NBID of its target. The ID of a directory is derived from the
+// sorted list of its entries, each entry contributing its name, type,
+// permission bits and ID, similar to git tree objects:
+//
+//	nbid.NewFields(Namespace, name1, type1, perm1, id1, name2, type2, perm2, id2, ...)
+//
+// where Namespace is the string "github.com/szkiba/nbid/tree", names are strings,
+// types are the strings "file", "dir" and "symlink", permissions are unsigned
+// integers (fs.FileMode.Perm) and IDs are NBIDs. Entries are sorted by the bytes of
+// their names. Therefore the root ID changes if any file content, name, type or
+// permission changes anywhere in the tree.
+//
+// Files are hashed in parallel by a pool of workers.
+package tree
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"sync"
+
+	"github.com/szkiba/nbid"
+)
+
+// Namespace is the first field of the encoding of directories.
+const Namespace = "github.com/szkiba/nbid/tree"
+
+var (
+	// ErrUnsupportedType is returned for entries other than regular files, directories and symbolic links.
+	ErrUnsupportedType = errors.New("tree: unsupported file type")
+
+	// ErrSymlink is returned for symbolic links if the file system doesn't implement ReadLinkFS.
+	ErrSymlink = errors.New("tree: file system doesn't support reading symbolic links")
+)
+
+// Type is the type of a tree entry.
+type Type string
+
+// Entry types.
+const (
+	File    Type = "file"
+	Dir     Type = "dir"
+	Symlink Type = "symlink"
+)
+
+// ReadLinkFS is a file system supporting symbolic links. The file system returned
+// by DirFS implements it, os.DirFS implements it only since Go 1.25.
+type ReadLinkFS interface {
+	fs.FS
+
+	// ReadLink returns the destination of the named symbolic link.
+	ReadLink(name string) (string, error)
+}
+
+// DirFS returns a file system for the tree rooted at the directory dir, like
+// os.DirFS, that also implements ReadLinkFS using os.Readlink.
+func DirFS(dir string) ReadLinkFS {
+	return &dirFS{FS: os.DirFS(dir), dir: dir}
+}
+
+type dirFS struct {
+	fs.FS
+	dir string
+}
+
+func (d *dirFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	return fs.ReadDir(d.FS, name)
+}
+
+func (d *dirFS) Stat(name string) (fs.FileInfo, error) {
+	return fs.Stat(d.FS, name)
+}
+
+func (d *dirFS) ReadLink(name string) (string, error) {
+	if !fs.ValidPath(name) {
+		return "", &fs.PathError{Op: "readlink", Path: name, Err: fs.ErrInvalid}
+	}
+
+	return os.Readlink(filepath.Join(d.dir, filepath.FromSlash(name)))
+}
+
+// Entry is an entry of a file tree.
+type Entry struct {
+	// Path is the slash separated path of the entry in the file system, "." for the root.
+	Path string
+	Type Type
+	Perm fs.FileMode
+	ID   nbid.NBID
+}
+
+// Options of Walk.
+type Options struct {
+	// Workers is the number of parallel file hashing workers, runtime.GOMAXPROCS(0) if zero.
+	Workers int
+}
+
+// Tree is the result of Walk.
+type Tree struct {
+	// Root is the ID of the root directory.
+	Root nbid.NBID
+	// Entries are all entries of the tree (including the root) sorted by path.
+	Entries []Entry
+}
+
+// Sum returns the ID of the directory root in fsys.
+func Sum(fsys fs.FS, root string) (nbid.NBID, error) {
+	t, err := Walk(fsys, root, Options{})
+	if err != nil {
+		return nbid.Nil, err
+	}
+
+	return t.Root, nil
+}
+
+// Walk computes the IDs of the directory root and all of its entries in fsys.
+func Walk(fsys fs.FS, root string, opts Options) (*Tree, error) {
+	var (
+		entries []*Entry
+		files   []*Entry
+	)
+
+	err := fs.WalkDir(fsys, root, func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		e := &Entry{Path: name, Perm: info.Mode().Perm()}
+
+		switch info.Mode().Type() {
+		case 0:
+			e.Type = File
+			files = append(files, e)
+		case fs.ModeDir:
+			e.Type = Dir
+		case fs.ModeSymlink:
+			e.Type = Symlink
+			if e.ID, err = readLink(fsys, name); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("%w: %s: %s", ErrUnsupportedType, name, info.Mode().Type())
+		}
+
+		entries = append(entries, e)
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := hashFiles(fsys, files, opts.Workers); err != nil {
+		return nil, err
+	}
+
+	if len(entries) == 1 && entries[0].Type == File {
+		return &Tree{Root: entries[0].ID, Entries: []Entry{*entries[0]}}, nil
+	}
+
+	return build(root, entries)
+}
+
+// build computes directory IDs bottom-up.
+func build(root string, entries []*Entry) (*Tree, error) {
+	children := make(map[string][]*Entry)
+
+	for _, e := range entries {
+		if e.Path != root {
+			dir := path.Dir(e.Path)
+			children[dir] = append(children[dir], e)
+		}
+	}
+
+	// WalkDir visits parents before children, so reverse order is bottom-up.
+	for i := len(entries) - 1; i >= 0; i-- {
+		e := entries[i]
+		if e.Type != Dir {
+			continue
+		}
+
+		id, err := dirID(children[e.Path])
+		if err != nil {
+			return nil, err
+		}
+
+		e.ID = id
+	}
+
+	t := &Tree{Root: entries[0].ID, Entries: make([]Entry, len(entries))}
+
+	for i, e := range entries {
+		t.Entries[i] = *e
+	}
+
+	sort.Slice(t.Entries, func(i, j int) bool {
+		return t.Entries[i].Path < t.Entries[j].Path
+	})
+
+	return t, nil
+}
+
+func dirID(children []*Entry) (nbid.NBID, error) {
+	sort.Slice(children, func(i, j int) bool {
+		return path.Base(children[i].Path) < path.Base(children[j].Path)
+	})
+
+	fields := make([]interface{}, 0, 1+len(children)*4) //nolint:gomnd
+
+	fields = append(fields, Namespace)
+
+	for _, c := range children {
+		fields = append(fields, path.Base(c.Path), string(c.Type), uint32(c.Perm), c.ID)
+	}
+
+	return nbid.NewFields(fields...)
+}
+
+func readLink(fsys fs.FS, name string) (nbid.NBID, error) {
+	lfs, ok := fsys.(ReadLinkFS)
+	if !ok {
+		return nbid.Nil, fmt.Errorf("%w: %s", ErrSymlink, name)
+	}
+
+	target, err := lfs.ReadLink(name)
+	if err != nil {
+		return nbid.Nil, err
+	}
+
+	return nbid.New([]byte(target)), nil
+}
+
+// hashFiles computes the IDs of files using a pool of workers.
+func hashFiles(fsys fs.FS, files []*Entry, workers int) error {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+
+	jobs := make(chan *Entry)
+	errs := make(chan error, workers)
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for e := range jobs {
+				id, err := hashFile(fsys, e.Path)
+				if err != nil {
+					errs <- err
+
+					return
+				}
+
+				e.ID = id
+			}
+		}()
+	}
+
+	var err error
+
+feed:
+	for _, e := range files {
+		select {
+		case jobs <- e:
+		case err = <-errs:
+			break feed
+		}
+	}
+
+	close(jobs)
+	wg.Wait()
+	close(errs)
+
+	if err == nil {
+		err = <-errs
+	}
+
+	return err
+}
+
+func hashFile(fsys fs.FS, name string) (nbid.NBID, error) {
+	f, err := fsys.Open(name)
+	if err != nil {
+		return nbid.Nil, err
+	}
+
+	defer f.Close() //nolint:errcheck
+
+	return nbid.NewReader(f)
+}
@@ -0,0 +1,155 @@
+// MIT License
+//
+// Copyright (c) 2021 Iván Szkiba
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package tree_test
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/szkiba/nbid"
+	"github.com/szkiba/nbid/tree"
+)
+
+func testFS() fstest.MapFS {
+	return fstest.MapFS{
+		"a.txt":     &fstest.MapFile{Data: []byte("hello"), Mode: 0o644},
+		"sub":       &fstest.MapFile{Mode: fs.ModeDir | 0o755},
+		"sub/b.txt": &fstest.MapFile{Data: []byte("world"), Mode: 0o755},
+	}
+}
+
+func TestWalk(t *testing.T) {
+	t.Parallel()
+
+	tr, err := tree.Walk(testFS(), ".", tree.Options{Workers: 2})
+
+	assert.Nil(t, err)
+	assert.Equal(t, "9R5GIO311VTOLB759VH9VFF15K", tr.Root.String())
+
+	paths := make([]string, 0, len(tr.Entries))
+	for _, e := range tr.Entries {
+		paths = append(paths, e.Path)
+	}
+
+	assert.Equal(t, []string{".", "a.txt", "sub", "sub/b.txt"}, paths)
+	assert.Equal(t, tr.Root, tr.Entries[0].ID)
+	assert.Equal(t, nbid.New([]byte("hello")), tr.Entries[1].ID)
+	assert.Equal(t, tree.File, tr.Entries[1].Type)
+	assert.Equal(t, fs.FileMode(0o644), tr.Entries[1].Perm)
+	assert.Equal(t, "DMSOP5SODMUVMO8RCR7V5O8GV0", tr.Entries[2].ID.String())
+	assert.Equal(t, tree.Dir, tr.Entries[2].Type)
+
+	sub, err := tree.Sum(testFS(), "sub")
+
+	assert.Nil(t, err)
+	assert.Equal(t, tr.Entries[2].ID, sub)
+
+	file, err := tree.Sum(testFS(), "a.txt")
+
+	assert.Nil(t, err)
+	assert.Equal(t, nbid.New([]byte("hello")), file)
+
+	empty, err := tree.Sum(fstest.MapFS{}, ".")
+
+	assert.Nil(t, err)
+	assert.Equal(t, "BSFFQBTNOUC4JR4NOBM1SGIVLO", empty.String())
+}
+
+func TestWalkChanges(t *testing.T) {
+	t.Parallel()
+
+	root, err := tree.Sum(testFS(), ".")
+	assert.Nil(t, err)
+
+	changes := map[string]func(fstest.MapFS){
+		"content": func(m fstest.MapFS) { m["sub/b.txt"].Data = []byte("World") },
+		"rename": func(m fstest.MapFS) {
+			m["c.txt"] = m["a.txt"]
+			delete(m, "a.txt")
+		},
+		"perm":  func(m fstest.MapFS) { m["a.txt"].Mode = 0o600 },
+		"add":   func(m fstest.MapFS) { m["sub/c.txt"] = &fstest.MapFile{Mode: 0o644} },
+		"empty": func(m fstest.MapFS) { m["dir"] = &fstest.MapFile{Mode: fs.ModeDir | 0o755} },
+		"move": func(m fstest.MapFS) {
+			m["sub/a.txt"] = m["a.txt"]
+			delete(m, "a.txt")
+		},
+	}
+
+	for name, change := range changes {
+		m := testFS()
+		change(m)
+
+		id, err := tree.Sum(m, ".")
+
+		assert.Nil(t, err, name)
+		assert.NotEqual(t, root, id, name)
+	}
+}
+
+func TestWalkOS(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	assert.Nil(t, os.WriteFile(filepath.Join(dir, "a.txt"), []byte("hello"), 0o644)) //nolint:gosec
+	assert.Nil(t, os.Mkdir(filepath.Join(dir, "sub"), 0o755))                        //nolint:gosec
+	assert.Nil(t, os.WriteFile(filepath.Join(dir, "sub", "b.txt"), []byte("world"), 0o755))
+	assert.Nil(t, os.Chmod(filepath.Join(dir, "sub", "b.txt"), 0o755)) // umask
+
+	id, err := tree.Sum(tree.DirFS(dir), ".")
+
+	assert.Nil(t, err)
+	assert.Equal(t, "9R5GIO311VTOLB759VH9VFF15K", id.String())
+
+	assert.Nil(t, os.Symlink("a.txt", filepath.Join(dir, "link")))
+
+	tr, err := tree.Walk(tree.DirFS(dir), ".", tree.Options{})
+
+	assert.Nil(t, err)
+	assert.NotEqual(t, id, tr.Root)
+	assert.Equal(t, tree.Symlink, tr.Entries[2].Type)
+	assert.Equal(t, nbid.New([]byte("a.txt")), tr.Entries[2].ID)
+
+	// hide ReadLink of the file system
+	_, err = tree.Sum(struct{ fs.FS }{tree.DirFS(dir)}, ".")
+	assert.True(t, errors.Is(err, tree.ErrSymlink))
+}
+
+func TestWalkErrors(t *testing.T) {
+	t.Parallel()
+
+	_, err := tree.Sum(testFS(), "missing")
+	assert.True(t, errors.Is(err, fs.ErrNotExist))
+
+	m := testFS()
+	m["pipe"] = &fstest.MapFile{Mode: fs.ModeNamedPipe}
+
+	_, err = tree.Sum(m, ".")
+	assert.True(t, errors.Is(err, tree.ErrUnsupportedType))
+}
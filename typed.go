@@ -0,0 +1,185 @@
+// MIT License
+//
+// Copyright (c) 2021 Iván Szkiba
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package nbid
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"sync"
+)
+
+// ID is an NBID typed by the kind of entity T it identifies.
+// IDs of different entity kinds cannot be mixed up without explicit conversion:
+//
+//	type User struct{ ... }
+//	type Order struct{ ... }
+//
+//	var uid nbid.ID[User] = nbid.NewID[User]([]byte("alice"))
+//	var oid nbid.ID[Order] = uid // compile error
+//
+// The representations (text, binary, JSON, SQL) are the same as of NBID.
+type ID[T any] NBID
+
+// Namespacer can be implemented by entity types to bind a namespace to their IDs.
+// The method is called on the zero value of the type.
+type Namespacer interface {
+	Namespace() NBID
+}
+
+var namespaces sync.Map // map[reflect.Type]NBID
+
+// BindNamespace binds the namespace ns to the entity type T, so name based IDs
+// of T created by NewID are derived using NewNamespaced with ns.
+// It takes precedence over the Namespacer implementation of T.
+// It is intended to be called during program initialization.
+func BindNamespace[T any](ns NBID) {
+	namespaces.Store(reflect.TypeOf((*T)(nil)).Elem(), ns)
+}
+
+// NamespaceOf returns the namespace bound to the entity type T, either by BindNamespace
+// or by implementing Namespacer. The second result is false if no namespace is bound.
+func NamespaceOf[T any]() (NBID, bool) {
+	return namespaceOfType(reflect.TypeOf((*T)(nil)).Elem())
+}
+
+func namespaceOfType(t reflect.Type) (NBID, bool) {
+	if ns, ok := namespaces.Load(t); ok {
+		return ns.(NBID), true //nolint:forcetypeassert
+	}
+
+	if n, ok := reflect.Zero(t).Interface().(Namespacer); ok {
+		return n.Namespace(), true
+	}
+
+	return Nil, false
+}
+
+// NewID returns a new name based ID of the entity type T.
+// If a namespace is bound to T (see NamespaceOf), the ID is derived using
+// NewNamespaced, otherwise using New.
+func NewID[T any](data []byte) ID[T] {
+	if ns, ok := NamespaceOf[T](); ok {
+		return ID[T](NewNamespaced(ns, data))
+	}
+
+	return ID[T](New(data))
+}
+
+// RandomID returns a new random ID of the entity type T.
+func RandomID[T any]() ID[T] {
+	return ID[T](Random())
+}
+
+// ParseID decodes s into an ID of the entity type T or returns an error.
+func ParseID[T any](s string) (ID[T], error) {
+	id, err := Parse(s)
+
+	return ID[T](id), err
+}
+
+// MustParseID decodes s into an ID of the entity type T or panics if the string cannot be parsed.
+func MustParseID[T any](s string) ID[T] {
+	return ID[T](MustParse(s))
+}
+
+// NBID returns the untyped NBID.
+func (id ID[T]) NBID() NBID {
+	return NBID(id)
+}
+
+// String returns the string form of the ID, see NBID.String.
+func (id ID[T]) String() string {
+	return NBID(id).String()
+}
+
+// Bytes returns the byte array representation of the ID.
+func (id ID[T]) Bytes() []byte {
+	return NBID(id).Bytes()
+}
+
+// IsNil returns true if this is a "nil" ID.
+func (id ID[T]) IsNil() bool {
+	return NBID(id).IsNil()
+}
+
+// Compare returns an integer comparing two IDs. It behaves just like `bytes.Compare`.
+func (id ID[T]) Compare(other ID[T]) int {
+	return NBID(id).Compare(NBID(other))
+}
+
+// Equal returns true if two IDs are equal.
+func (id ID[T]) Equal(other ID[T]) bool {
+	return NBID(id).Equal(NBID(other))
+}
+
+// MarshalText implements encoding/text TextMarshaler interface.
+func (id ID[T]) MarshalText() ([]byte, error) {
+	return NBID(id).MarshalText()
+}
+
+// UnmarshalText implements encoding/text TextUnmarshaler interface.
+func (id *ID[T]) UnmarshalText(text []byte) error {
+	return (*NBID)(id).UnmarshalText(text)
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler.
+func (id ID[T]) MarshalBinary() ([]byte, error) {
+	return NBID(id).MarshalBinary()
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+func (id *ID[T]) UnmarshalBinary(data []byte) error {
+	return (*NBID)(id).UnmarshalBinary(data)
+}
+
+// MarshalJSON implements encoding/json Marshaler interface.
+func (id ID[T]) MarshalJSON() ([]byte, error) {
+	return NBID(id).MarshalJSON()
+}
+
+// UnmarshalJSON implements encoding/json Unmarshaler interface.
+func (id *ID[T]) UnmarshalJSON(b []byte) error {
+	return (*NBID)(id).UnmarshalJSON(b)
+}
+
+// Scan implements sql.Scanner.
+func (id *ID[T]) Scan(src interface{}) error {
+	return (*NBID)(id).Scan(src)
+}
+
+// Value implements sql.Valuer.
+func (id ID[T]) Value() (driver.Value, error) {
+	return NBID(id).Value()
+}
+
+// LogValue implements slog.LogValuer.
+func (id ID[T]) LogValue() slog.Value {
+	return NBID(id).LogValue()
+}
+
+// Format implements fmt.Formatter, see NBID.Format.
+func (id ID[T]) Format(f fmt.State, verb rune) {
+	NBID(id).Format(f, verb)
+}
@@ -0,0 +1,125 @@
+// MIT License
+//
+// Copyright (c) 2021 Iván Szkiba
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package nbid_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/szkiba/nbid"
+)
+
+type user struct{}
+
+func (user) Namespace() nbid.NBID {
+	return nbid.New([]byte("user"))
+}
+
+type order struct{}
+
+type product struct{}
+
+func init() { //nolint:gochecknoinits
+	nbid.BindNamespace[order](nbid.New([]byte("order")))
+}
+
+func TestNewID(t *testing.T) {
+	t.Parallel()
+
+	name := []byte("42")
+
+	uid := nbid.NewID[user](name)
+	oid := nbid.NewID[order](name)
+	pid := nbid.NewID[product](name)
+
+	assert.Equal(t, nbid.NewNamespaced(nbid.New([]byte("user")), name), uid.NBID())
+	assert.Equal(t, nbid.NewNamespaced(nbid.New([]byte("order")), name), oid.NBID())
+	assert.Equal(t, nbid.New(name), pid.NBID())
+
+	_, ok := nbid.NamespaceOf[product]()
+	assert.False(t, ok)
+
+	assert.False(t, nbid.RandomID[user]().IsNil())
+	assert.True(t, nbid.ID[user]{}.IsNil())
+}
+
+func TestIDMethods(t *testing.T) {
+	t.Parallel()
+
+	id := nbid.MustParseID[user]("QUKFNCO7QU098QEAJAUB021E9S")
+	raw := nbid.MustParse("QUKFNCO7QU098QEAJAUB021E9S")
+
+	assert.Equal(t, raw.String(), id.String())
+	assert.Equal(t, raw.Bytes(), id.Bytes())
+	assert.Equal(t, raw.String(), fmt.Sprint(id))
+	assert.Equal(t, fmt.Sprintf("%x", raw), fmt.Sprintf("%x", id))
+	assert.True(t, id.Equal(nbid.ID[user](raw)))
+	assert.Equal(t, 0, id.Compare(nbid.ID[user](raw)))
+	assert.Less(t, nbid.ID[user]{}.Compare(id), 0)
+
+	_, err := nbid.ParseID[user]("XXX")
+	assert.Error(t, err)
+
+	// text
+	text, err := id.MarshalText()
+	assert.Nil(t, err)
+
+	var textID nbid.ID[user]
+
+	assert.Nil(t, textID.UnmarshalText(text))
+	assert.Equal(t, id, textID)
+
+	// binary
+	bin, err := id.MarshalBinary()
+	assert.Nil(t, err)
+
+	var binID nbid.ID[user]
+
+	assert.Nil(t, binID.UnmarshalBinary(bin))
+	assert.Equal(t, id, binID)
+
+	// JSON
+	type x struct {
+		Owner nbid.ID[user] `json:"owner"`
+	}
+
+	b, err := json.Marshal(x{Owner: id})
+	assert.Nil(t, err)
+	assert.Equal(t, `{"owner":"QUKFNCO7QU098QEAJAUB021E9S"}`, string(b))
+
+	var data x
+
+	assert.Nil(t, json.Unmarshal(b, &data))
+	assert.Equal(t, id, data.Owner)
+
+	// SQL
+	val, err := id.Value()
+	assert.Nil(t, err)
+
+	var sqlID nbid.ID[user]
+
+	assert.Nil(t, sqlID.Scan(val))
+	assert.Equal(t, id, sqlID)
+}